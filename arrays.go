@@ -0,0 +1,52 @@
+package supervillain
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// ArrayMode decides how fixed size arrays, such as `[3]float64`, are converted.
+type ArrayMode int
+
+const (
+	// ArrayLength converts arrays to an array of the element schema with a
+	// fixed length, such as `z.number().array().length(3)`.
+	ArrayLength ArrayMode = iota
+	// ArrayTuple converts arrays to a tuple with the element schema repeated
+	// for each index, such as `z.tuple([z.number(), z.number(), z.number()])`.
+	ArrayTuple
+)
+
+type arrayModeOption ArrayMode
+
+func (a arrayModeOption) apply(c *Converter) {
+	c.arrayMode = ArrayMode(a)
+}
+
+// WithArrayMode sets how fixed size arrays are converted, the default is
+// ArrayLength.
+func WithArrayMode(m ArrayMode) Option {
+	return arrayModeOption(m)
+}
+
+// converts a fixed size array. unlike []byte, encoding/json writes byte arrays
+// as arrays of numbers so they are not special-cased here.
+func (c *Converter) convertArray(t reflect.Type, name string, indent int) Schema {
+	c.pushPath("[]")
+	defer c.popPath()
+
+	return c.arraySchema(c.convertSchema(t.Elem(), name, indent), t.Len())
+}
+
+// builds the schema for an array of n elements from the schema of an element.
+func (c *Converter) arraySchema(elem Schema, n int) Schema {
+	if c.arrayMode == ArrayTuple {
+		elems := make([]Schema, n)
+		for i := range elems {
+			elems[i] = elem
+		}
+		return &Tuple{Items: elems}
+	}
+
+	return &Array{Items: elem, Checks: []Check{{CheckLength, strconv.Itoa(n)}}}
+}
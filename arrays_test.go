@@ -0,0 +1,88 @@
+package supervillain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Shape struct {
+	Position [3]float64
+	Colour   [3]uint8 `json:"colour,omitempty"`
+	Checksum [4]byte
+	Tags     [2]string
+	Empty    [0]int `json:"empty,omitempty"`
+}
+
+func TestArrayLength(t *testing.T) {
+	assert.Equal(t,
+		`export const ShapeSchema = z.object({
+  Position: z.number().array().length(3),
+  colour: z.number().int().min(0).max(255).array().length(3),
+  Checksum: z.number().int().min(0).max(255).array().length(4),
+  Tags: z.string().array().length(2),
+  empty: z.number().int().array().length(0).optional(),
+})
+export type Shape = z.infer<typeof ShapeSchema>
+
+`,
+		StructToZodSchema(Shape{}))
+}
+
+func TestArrayTuple(t *testing.T) {
+	type Line struct {
+		Points [2]struct {
+			X float64
+			Y float64
+		}
+		Colour [3]uint8
+	}
+	assert.Equal(t,
+		`export const LineSchema = z.object({
+  Points: z.tuple([z.object({
+    X: z.number(),
+    Y: z.number(),
+  }), z.object({
+    X: z.number(),
+    Y: z.number(),
+  })]),
+  Colour: z.tuple([z.number().int().min(0).max(255), z.number().int().min(0).max(255), z.number().int().min(0).max(255)]),
+})
+export type Line = z.infer<typeof LineSchema>
+
+`,
+		StructToZodSchema(Line{}, WithArrayMode(ArrayTuple)))
+}
+
+type Mesh struct {
+	Vertex   [3]float64
+	Children [2][]Mesh
+}
+
+func TestArrayRecursive(t *testing.T) {
+	assert.Equal(t,
+		`export type Mesh = {
+  Vertex: number[]
+  Children: Mesh[][]
+}
+export const MeshSchema: z.ZodType<Mesh> = z.object({
+  Vertex: z.number().array().length(3),
+  Children: z.lazy(() => MeshSchema).array().array().length(2),
+})
+
+`,
+		StructToZodSchema(Mesh{}))
+
+	assert.Equal(t,
+		`export type Mesh = {
+  Vertex: [number, number, number]
+  Children: [Mesh[], Mesh[]]
+}
+export const MeshSchema: z.ZodType<Mesh> = z.object({
+  Vertex: z.tuple([z.number(), z.number(), z.number()]),
+  Children: z.tuple([z.lazy(() => MeshSchema).array(), z.lazy(() => MeshSchema).array()]),
+})
+
+`,
+		StructToZodSchema(Mesh{}, WithArrayMode(ArrayTuple)))
+}
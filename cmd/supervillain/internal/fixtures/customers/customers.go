@@ -0,0 +1,6 @@
+package customers
+
+// Customer places orders.
+type Customer struct {
+	Name string // the full name
+}
@@ -0,0 +1,17 @@
+package mappings
+
+import (
+	"reflect"
+
+	"github.com/Southclaws/supervillain"
+)
+
+// ID is written as a string by its custom mapping.
+type ID int
+
+var (
+	IDType = "github.com/Southclaws/supervillain/cmd/supervillain/internal/fixtures/mappings.ID"
+	IDFunc = func(c *supervillain.Converter, t reflect.Type, s, g string, i int) string {
+		return "z.string().uuid()"
+	}
+)
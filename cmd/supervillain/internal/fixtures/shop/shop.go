@@ -0,0 +1,69 @@
+package shop
+
+import (
+	"github.com/Southclaws/supervillain/cmd/supervillain/internal/fixtures/customers"
+	"github.com/Southclaws/supervillain/cmd/supervillain/internal/fixtures/mappings"
+)
+
+// Order is placed by a customer.
+//
+//supervillain:export
+type Order struct {
+	ID       mappings.ID
+	Customer customers.Customer
+	Status   Status
+	Priority Priority
+	Lines    []Line `json:"lines"`
+}
+
+type (
+	//supervillain:export
+	Line struct {
+		Product  string
+		Quantity int `validate:"min=1"`
+	}
+
+	// Basket is not annotated.
+	Basket struct {
+		Lines []Line
+	}
+)
+
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusShipped Status = "shipped"
+)
+
+// Priority is written by its String method.
+type Priority int
+
+const (
+	PriorityNormal Priority = iota
+	PriorityUrgent
+)
+
+func (p Priority) String() string {
+	if p == PriorityUrgent {
+		return "urgent"
+	}
+	return "normal"
+}
+
+// Page is generic, so it is only converted where it is used.
+type Page[T any] struct {
+	Items []T // the items on this page
+}
+
+// Catalogue lists lines a page at a time.
+//
+//supervillain:export
+type Catalogue struct {
+	Lines Page[Line]
+}
+
+type unexported struct{}
+
+// Alias is not a new type.
+type Alias = Order
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// the comment that marks a type for export when only annotated types are
+// wanted.
+const exportAnnotation = "//supervillain:export"
+
+// a package and the struct types in it that are converted, which may be none
+// when the package is only there for its enums or for the types that use it.
+type target struct {
+	path  string
+	types []string
+}
+
+// loads the packages matching the patterns and finds the types to convert in
+// each of them, along with the directory of the module that the throwaway
+// program is run in.
+func loadTargets(patterns []string, annotated bool) ([]target, string, error) {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedModule,
+	}, patterns...)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(pkgs) == 0 {
+		return nil, "", fmt.Errorf("no packages found for %s", strings.Join(patterns, " "))
+	}
+
+	targets := []target{}
+	moduleDir := ""
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, "", fmt.Errorf("loading %s: %w", pkg.PkgPath, pkg.Errors[0])
+		}
+		if pkg.Name == "main" {
+			return nil, "", fmt.Errorf("%s is a main package, which cannot be imported", pkg.PkgPath)
+		}
+		if pkg.Module == nil {
+			return nil, "", fmt.Errorf("%s is not in a module", pkg.PkgPath)
+		}
+		if moduleDir == "" {
+			moduleDir = pkg.Module.Dir
+		}
+
+		t := target{path: pkg.PkgPath}
+		for _, file := range pkg.Syntax {
+			t.types = append(t.types, exportedStructs(pkg.Types, file, annotated)...)
+		}
+
+		// files are walked in the order they are loaded, which is not always
+		// the order of their names.
+		sort.Slice(t.types, func(i, j int) bool {
+			return pkg.Types.Scope().Lookup(t.types[i]).Pos() < pkg.Types.Scope().Lookup(t.types[j]).Pos()
+		})
+		targets = append(targets, t)
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].path < targets[j].path
+	})
+
+	return targets, moduleDir, nil
+}
+
+// finds the exported struct types declared in a file. generic types cannot be
+// converted without type arguments so they are left to be found through the
+// fields that use them.
+func exportedStructs(pkg *types.Package, file *ast.File, annotated bool) []string {
+	names := []string{}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			spec := spec.(*ast.TypeSpec)
+			if !spec.Name.IsExported() || spec.Assign.IsValid() || spec.TypeParams != nil {
+				continue
+			}
+			if annotated && !hasAnnotation(spec.Doc) && !(len(gen.Specs) == 1 && hasAnnotation(gen.Doc)) {
+				continue
+			}
+			obj := pkg.Scope().Lookup(spec.Name.Name)
+			if obj == nil {
+				continue
+			}
+			if _, ok := obj.Type().Underlying().(*types.Struct); !ok {
+				continue
+			}
+			names = append(names, spec.Name.Name)
+		}
+	}
+	return names
+}
+
+func hasAnnotation(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		if strings.TrimSpace(c.Text) == exportAnnotation {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fixtures = "github.com/Southclaws/supervillain/cmd/supervillain/internal/fixtures"
+
+func TestLoadTargets(t *testing.T) {
+	targets, moduleDir, err := loadTargets([]string{"./internal/fixtures/..."}, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, []target{
+		{path: fixtures + "/customers", types: []string{"Customer"}},
+		{path: fixtures + "/mappings"},
+		{path: fixtures + "/shop", types: []string{"Order", "Line", "Basket", "Catalogue"}},
+	}, targets)
+	assert.NotEmpty(t, moduleDir)
+}
+
+func TestLoadTargetsAnnotated(t *testing.T) {
+	targets, _, err := loadTargets([]string{"./internal/fixtures/..."}, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, []target{
+		{path: fixtures + "/customers"},
+		{path: fixtures + "/mappings"},
+		{path: fixtures + "/shop", types: []string{"Order", "Line", "Catalogue"}},
+	}, targets)
+}
+
+func TestLoadTargetsMain(t *testing.T) {
+	_, _, err := loadTargets([]string{"."}, false)
+	assert.EqualError(t, err, "github.com/Southclaws/supervillain/cmd/supervillain is a main package, which cannot be imported")
+}
@@ -0,0 +1,176 @@
+// Command supervillain converts the struct types in Go packages to Zod
+// schemas and writes them to TypeScript modules, one for each package, which
+// import the schemas they use from each other.
+//
+// It loads the packages, finds the exported struct types in them and builds a
+// throwaway program that imports them and runs the converter, so it must be
+// run inside a module that requires github.com/Southclaws/supervillain and
+// this command, which is a module of its own. It is made for go:generate:
+//
+//	//go:generate go run github.com/Southclaws/supervillain/cmd/supervillain -out ../web/src/schemas .
+//
+// Usage:
+//
+//	supervillain [flags] packages...
+//
+// The flags are:
+//
+//	-out dir
+//		the directory the TypeScript files are written to. (default ".")
+//	-root path
+//		the package path that module paths are relative to. (default the
+//		longest path that every package is in)
+//	-index
+//		write an index.ts that re-exports every module.
+//	-ext extension
+//		added to the paths of imports between modules, such as .js.
+//	-import module
+//		the module zod is imported from, such as zod/v4. (default "zod")
+//	-namespace
+//		import zod with import * as z.
+//	-eslint-disable
+//		add an eslint-disable comment to every module.
+//	-ts-nocheck
+//		add a @ts-nocheck comment to every module.
+//	-annotated
+//		only convert types with a //supervillain:export comment.
+//	-custom type=package.Func
+//		use the supervillain.CustomFn variable package.Func for the type with
+//		the full name type. May be given more than once.
+//	-enums
+//		find enums from the constants in the packages. Every named type with
+//		at least two exported constants, or one and a //supervillain:enum
+//		comment, is treated as an enum.
+//	-docs
+//		add the doc comments of types and fields to the schemas.
+//	-validate key
+//		translate the validation rules in the struct tag key.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// the values of a repeated flag.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "supervillain:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("supervillain", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: supervillain [flags] packages...")
+		flags.PrintDefaults()
+	}
+
+	out := flags.String("out", ".", "the directory the TypeScript files are written to")
+	root := flags.String("root", "", "the package path that module paths are relative to (default the longest path that every package is in)")
+	index := flags.Bool("index", false, "write an index.ts that re-exports every module")
+	ext := flags.String("ext", "", "added to the paths of imports between modules, such as .js")
+	zodImport := flags.String("import", "zod", "the module zod is imported from, such as zod/v4")
+	namespace := flags.Bool("namespace", false, "import zod with import * as z")
+	eslintDisable := flags.Bool("eslint-disable", false, "add an eslint-disable comment to every module")
+	tsNoCheck := flags.Bool("ts-nocheck", false, "add a @ts-nocheck comment to every module")
+	annotated := flags.Bool("annotated", false, "only convert types with a "+exportAnnotation+" comment")
+	custom := listFlag{}
+	flags.Var(&custom, "custom", "a custom mapping from a type to a CustomFn variable, as type=package.Func")
+	enums := flags.Bool("enums", false, "find enums from the constants in the packages")
+	docs := flags.Bool("docs", false, "add the doc comments of types and fields to the schemas")
+	validate := flags.String("validate", "", "translate the validation rules in this struct tag")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("no packages given")
+	}
+
+	opts := options{
+		enums:           *enums,
+		docs:            *docs,
+		validationTags:  *validate,
+		root:            *root,
+		index:           *index,
+		importExtension: *ext,
+		preamble: programPreamble{
+			Import:        *zodImport,
+			Namespace:     *namespace,
+			ESLintDisable: *eslintDisable,
+			TSNoCheck:     *tsNoCheck,
+		},
+	}
+	for _, c := range custom {
+		m, err := parseCustomMapping(c)
+		if err != nil {
+			return err
+		}
+		opts.custom = append(opts.custom, m)
+	}
+
+	targets, moduleDir, err := loadTargets(flags.Args(), *annotated)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, t := range targets {
+		found = found || len(t.types) > 0
+	}
+	if !found {
+		return errors.New("no types found to convert")
+	}
+
+	p, err := newProgram(targets, opts)
+	if err != nil {
+		return err
+	}
+	files, err := p.run(moduleDir)
+	if err != nil {
+		return err
+	}
+
+	return writeFiles(*out, files)
+}
+
+func writeFiles(dir string, files map[string]string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(files[name]), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
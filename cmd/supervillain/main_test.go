@@ -0,0 +1,153 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	out := t.TempDir()
+	require.NoError(t, run([]string{
+		"-out", out,
+		"-annotated",
+		"-enums",
+		"-docs",
+		"-index",
+		"-validate", "validate",
+		"-custom", fixtures + "/mappings.ID=" + fixtures + "/mappings.IDFunc",
+		"./internal/fixtures/...",
+	}))
+
+	read := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(out, name))
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	assert.Equal(t,
+		`// Code generated by supervillain; DO NOT EDIT.
+//
+// Generated from:
+//   github.com/Southclaws/supervillain/cmd/supervillain/internal/fixtures/shop.Catalogue
+//   github.com/Southclaws/supervillain/cmd/supervillain/internal/fixtures/shop.Line
+//   github.com/Southclaws/supervillain/cmd/supervillain/internal/fixtures/shop.Order
+//   github.com/Southclaws/supervillain/cmd/supervillain/internal/fixtures/shop.Page
+//   github.com/Southclaws/supervillain/cmd/supervillain/internal/fixtures/shop.Priority
+//   github.com/Southclaws/supervillain/cmd/supervillain/internal/fixtures/shop.Status
+
+import { z } from "zod"
+import { CustomerSchema } from "./customers"
+
+export const LineSchema = z.object({
+  Product: z.string(),
+  Quantity: z.number().int().min(1),
+})
+export type Line = z.infer<typeof LineSchema>
+
+export const PageSchema = <T extends z.ZodTypeAny>(t: T) => z.object({
+  /** the items on this page */
+  Items: t.array().nullable().describe("the items on this page"),
+}).describe("Page is generic, so it is only converted where it is used.")
+/** Page is generic, so it is only converted where it is used. */
+export type Page<T extends z.ZodTypeAny> = z.infer<ReturnType<typeof PageSchema<T>>>
+
+export const CatalogueSchema = z.object({
+  Lines: PageSchema(LineSchema),
+}).describe("Catalogue lists lines a page at a time.")
+/** Catalogue lists lines a page at a time. */
+export type Catalogue = z.infer<typeof CatalogueSchema>
+
+export const PrioritySchema = z.enum(["normal", "urgent"]).describe("Priority is written by its String method.")
+/** Priority is written by its String method. */
+export type Priority = z.infer<typeof PrioritySchema>
+export const Priority = {
+  Normal: "normal",
+  Urgent: "urgent",
+} as const
+
+export const StatusSchema = z.enum(["pending", "shipped"])
+export type Status = z.infer<typeof StatusSchema>
+export const Status = {
+  Pending: "pending",
+  Shipped: "shipped",
+} as const
+
+export const OrderSchema = z.object({
+  ID: z.string().uuid(),
+  Customer: CustomerSchema,
+  Status: StatusSchema,
+  Priority: PrioritySchema,
+  lines: LineSchema.array().nullable(),
+}).describe("Order is placed by a customer.")
+/** Order is placed by a customer. */
+export type Order = z.infer<typeof OrderSchema>
+
+`,
+		read("shop.ts"))
+
+	assert.Equal(t,
+		`// Code generated by supervillain; DO NOT EDIT.
+//
+// Generated from:
+//   github.com/Southclaws/supervillain/cmd/supervillain/internal/fixtures/customers.Customer
+
+import { z } from "zod"
+
+export const CustomerSchema = z.object({
+  /** the full name */
+  Name: z.string().describe("the full name"),
+}).describe("Customer places orders.")
+/** Customer places orders. */
+export type Customer = z.infer<typeof CustomerSchema>
+
+`,
+		read("customers.ts"))
+
+	assert.Equal(t,
+		`// Code generated by supervillain; DO NOT EDIT.
+
+export * from "./customers"
+export * from "./shop"
+`,
+		read("index.ts"))
+
+	entries, err := os.ReadDir(".")
+	require.NoError(t, err)
+	for _, e := range entries {
+		assert.NotContains(t, e.Name(), "supervillain-", "the generator was not removed")
+	}
+}
+
+func TestRunRoot(t *testing.T) {
+	out := t.TempDir()
+	require.NoError(t, run([]string{
+		"-out", out,
+		"-root", "github.com/Southclaws/supervillain/cmd",
+		"-ext", ".js",
+		"-import", "zod/v4",
+		"-namespace",
+		"-eslint-disable",
+		"-ts-nocheck",
+		"-custom", fixtures + "/mappings.ID=" + fixtures + "/mappings.IDFunc",
+		"./internal/fixtures/shop",
+	}))
+
+	b, err := os.ReadFile(filepath.Join(out, "supervillain/internal/fixtures/shop.ts"))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `/* eslint-disable */
+// @ts-nocheck
+
+import * as z from "zod/v4"
+import { CustomerSchema } from "./customers.js"
+`)
+	// enums are only found with -enums.
+	assert.Contains(t, string(b), "Status: z.string(),")
+}
+
+func TestRunNoPackages(t *testing.T) {
+	assert.EqualError(t, run([]string{"-out", t.TempDir()}), "no packages given")
+}
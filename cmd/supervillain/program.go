@@ -0,0 +1,361 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Southclaws/supervillain/source"
+)
+
+// a custom mapping from the full name of a type, as used for the map given to
+// supervillain.NewConverter, to a supervillain.CustomFn variable in another
+// package.
+type customMapping struct {
+	typ  string
+	path string
+	name string
+}
+
+// parses a custom mapping flag, such as
+// `github.com/shopspring/decimal.Decimal=github.com/Southclaws/supervillain/custom/decimal.DecimalFunc`.
+func parseCustomMapping(s string) (customMapping, error) {
+	typ, fn, ok := strings.Cut(s, "=")
+	if !ok || typ == "" {
+		return customMapping{}, fmt.Errorf("custom mapping %q is not of the form type=package.Func", s)
+	}
+	i := strings.LastIndex(fn, ".")
+	if i <= 0 || i == len(fn)-1 {
+		return customMapping{}, fmt.Errorf("custom mapping %q is not of the form type=package.Func", s)
+	}
+	return customMapping{typ: typ, path: fn[:i], name: fn[i+1:]}, nil
+}
+
+// everything the throwaway program needs to convert the targets.
+type program struct {
+	Imports        []programImport
+	Custom         []programCustom
+	Enums          []programEnum
+	Docs           map[string]string
+	UseDocs        bool
+	ValidationTags string
+	Layout         programLayout
+	Preamble       programPreamble
+	Types          []string
+}
+
+type programImport struct {
+	Alias string
+	Path  string
+}
+
+type programCustom struct {
+	Type  string
+	Value string
+}
+
+type programEnum struct {
+	// a constant of the enum type, to get the type from.
+	Type      string
+	Constants []programConstant
+}
+
+type programConstant struct {
+	Name  string
+	Value string
+}
+
+type programLayout struct {
+	Root            string
+	Index           bool
+	ImportExtension string
+}
+
+type programPreamble struct {
+	Import        string
+	Namespace     bool
+	ESLintDisable bool
+	TSNoCheck     bool
+}
+
+type options struct {
+	custom         []customMapping
+	enums          bool
+	docs           bool
+	validationTags string
+	// the package path that module paths are relative to, which defaults to
+	// the longest path that every package is in.
+	root            string
+	index           bool
+	importExtension string
+	// how zod is imported and which directives are added.
+	preamble programPreamble
+}
+
+// builds the program for a set of targets. enums and docs are found in the
+// source code here, so that the program only needs supervillain and the
+// packages being converted.
+func newProgram(targets []target, opts options) (program, error) {
+	p := program{
+		UseDocs:        opts.docs,
+		ValidationTags: opts.validationTags,
+		Layout:         programLayout{Root: opts.root, Index: opts.index, ImportExtension: opts.importExtension},
+		Preamble:       opts.preamble,
+	}
+	aliases := map[string]string{}
+	alias := func(path string) string {
+		if a, ok := aliases[path]; ok {
+			return a
+		}
+		a := fmt.Sprintf("p%d", len(aliases))
+		aliases[path] = a
+		p.Imports = append(p.Imports, programImport{Alias: a, Path: path})
+		return a
+	}
+
+	patterns := []string{}
+	for _, t := range targets {
+		patterns = append(patterns, t.path)
+		for _, typ := range t.types {
+			p.Types = append(p.Types, fmt.Sprintf("%s.%s{}", alias(t.path), typ))
+		}
+	}
+	if p.Layout.Root == "" {
+		p.Layout.Root = commonPath(patterns)
+	}
+
+	for _, m := range opts.custom {
+		p.Custom = append(p.Custom, programCustom{Type: m.typ, Value: fmt.Sprintf("%s.%s", alias(m.path), m.name)})
+	}
+
+	if opts.enums {
+		enums, err := source.LoadEnums(patterns...)
+		if err != nil {
+			return program{}, err
+		}
+		keys := make([]string, 0, len(enums))
+		for key := range enums {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			enum := enums[key]
+			a := alias(enum.PkgPath)
+			e := programEnum{Type: fmt.Sprintf("%s.%s", a, enum.Constants[0].Name)}
+			for _, c := range enum.Constants {
+				e.Constants = append(e.Constants, programConstant{Name: c.Name, Value: fmt.Sprintf("%s.%s", a, c.Name)})
+			}
+			p.Enums = append(p.Enums, e)
+		}
+	}
+
+	if opts.docs {
+		docs, err := source.LoadDocs(patterns...)
+		if err != nil {
+			return program{}, err
+		}
+		p.Docs = map[string]string{}
+		for key, doc := range docs {
+			if doc.Doc != "" {
+				p.Docs[key] = doc.Doc
+			}
+			for field, text := range doc.Fields {
+				p.Docs[key+"."+field] = text
+			}
+		}
+	}
+
+	return p, nil
+}
+
+var programTemplate = template.Must(template.New("program").Parse(`// Code generated by supervillain. DO NOT EDIT.
+
+package main
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/Southclaws/supervillain"
+{{range .Imports}}
+	{{.Alias}} {{printf "%q" .Path}}
+{{- end}}
+)
+
+type enumSource map[reflect.Type][]supervillain.EnumValue
+
+func (e enumSource) EnumValues(t reflect.Type) ([]supervillain.EnumValue, bool) {
+	values, ok := e[t]
+	return values, ok
+}
+
+// the JSON of an enum value from its String method, as long as it has no
+// MarshalJSON or MarshalText method that is used instead.
+func stringJSON(v any) string {
+	p := reflect.New(reflect.TypeOf(v))
+	p.Elem().Set(reflect.ValueOf(v))
+	for _, x := range []any{v, p.Interface()} {
+		switch x.(type) {
+		case json.Marshaler, encoding.TextMarshaler:
+			return ""
+		}
+	}
+	for _, x := range []any{v, p.Interface()} {
+		if s, ok := x.(fmt.Stringer); ok {
+			b, _ := json.Marshal(s.String())
+			return string(b)
+		}
+	}
+	return ""
+}
+
+var enums = enumSource{
+{{- range .Enums}}
+	reflect.TypeOf({{.Type}}): {
+	{{- range .Constants}}
+		{Name: {{printf "%q" .Name}}, Value: {{.Value}}, JSON: stringJSON({{.Value}})},
+	{{- end}}
+	},
+{{- end}}
+}
+
+type docSource map[string]string
+
+// the key of a type, without the type arguments of instantiations of generic
+// types such as Page[main.User], which are documented where they are declared.
+func docKey(t reflect.Type) string {
+	name, _, _ := strings.Cut(t.Name(), "[")
+	return t.PkgPath() + "." + name
+}
+
+func (d docSource) TypeDoc(t reflect.Type) string {
+	return d[docKey(t)]
+}
+
+func (d docSource) FieldDoc(t reflect.Type, field string) string {
+	return d[docKey(t)+"."+field]
+}
+
+var docs = docSource{
+{{- range $key, $doc := .Docs}}
+	{{printf "%q" $key}}: {{printf "%q" $doc}},
+{{- end}}
+}
+
+var custom = map[string]supervillain.CustomFn{
+{{- range .Custom}}
+	{{printf "%q" .Type}}: {{.Value}},
+{{- end}}
+}
+
+func main() {
+	options := []supervillain.Option{supervillain.WithEnumSource(enums)}
+{{- if .UseDocs}}
+	options = append(options, supervillain.WithDocSource(docs))
+{{- end}}
+{{- if .ValidationTags}}
+	options = append(options, supervillain.WithValidationTags({{printf "%q" .ValidationTags}}))
+{{- end}}
+
+	options = append(options, supervillain.WithModuleLayout(supervillain.ModuleLayout{
+		Root:            {{printf "%q" .Layout.Root}},
+		Index:           {{.Layout.Index}},
+		ImportExtension: {{printf "%q" .Layout.ImportExtension}},
+	}))
+	options = append(options, supervillain.WithPreamble(supervillain.Preamble{
+		Import:        {{printf "%q" .Preamble.Import}},
+		Namespace:     {{.Preamble.Namespace}},
+		Banner:        true,
+		ESLintDisable: {{.Preamble.ESLintDisable}},
+		TSNoCheck:     {{.Preamble.TSNoCheck}},
+	}))
+
+	c := supervillain.NewConverter(custom, options...)
+	files, err := c.ConvertModulesE([]interface{}{
+	{{- range .Types}}
+		{{.}},
+	{{- end}}
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(files); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`))
+
+// gets the longest package path that every path is in, or is.
+func commonPath(paths []string) string {
+	common := strings.Split(paths[0], "/")
+	for _, path := range paths[1:] {
+		parts := strings.Split(path, "/")
+		i := 0
+		for i < len(common) && i < len(parts) && common[i] == parts[i] {
+			i++
+		}
+		common = common[:i]
+	}
+	return strings.Join(common, "/")
+}
+
+// renders the source code of the program.
+func (p program) source() ([]byte, error) {
+	b := bytes.Buffer{}
+	if err := programTemplate.Execute(&b, p); err != nil {
+		return nil, err
+	}
+	return format.Source(b.Bytes())
+}
+
+// writes the program to a temporary directory in the module and runs it. the
+// program must be inside the module so that it can import the packages being
+// converted, which means the module must require supervillain and any package
+// that custom mappings come from. it returns the output of each module by its
+// file path.
+func (p program) run(moduleDir string) (map[string]string, error) {
+	src, err := p.source()
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp(moduleDir, "supervillain-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), src, 0o644); err != nil {
+		return nil, err
+	}
+
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	cmd := exec.Command("go", "run", "main.go")
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("running the generator: %w\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	files := map[string]string{}
+	if err := json.Unmarshal(stdout.Bytes(), &files); err != nil {
+		return nil, fmt.Errorf("reading the output of the generator: %w", err)
+	}
+	return files, nil
+}
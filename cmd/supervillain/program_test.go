@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCustomMapping(t *testing.T) {
+	m, err := parseCustomMapping("github.com/shopspring/decimal.Decimal=github.com/Southclaws/supervillain/custom/decimal.DecimalFunc")
+	require.NoError(t, err)
+	assert.Equal(t, customMapping{
+		typ:  "github.com/shopspring/decimal.Decimal",
+		path: "github.com/Southclaws/supervillain/custom/decimal",
+		name: "DecimalFunc",
+	}, m)
+
+	for _, s := range []string{"", "github.com/shopspring/decimal.Decimal", "=decimal.DecimalFunc", "decimal.Decimal=DecimalFunc", "decimal.Decimal=decimal."} {
+		_, err := parseCustomMapping(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestCommonPath(t *testing.T) {
+	assert.Equal(t, "example.com/api/users", commonPath([]string{"example.com/api/users"}))
+	assert.Equal(t, "example.com/api", commonPath([]string{"example.com/api/users", "example.com/api/billing/invoices"}))
+	assert.Equal(t, "", commonPath([]string{"example.com/api", "example.org/api"}))
+}
+
+func TestProgramRunError(t *testing.T) {
+	p := program{Types: []string{"struct{ C chan int }{}"}}
+	_, err := p.run(".")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported_type")
+}
@@ -34,7 +34,7 @@ export type Profile = z.infer<typeof ProfileSchema>
 
 export const UserSchema = z.object({
   MaybeName: z.string().optional(),
-  MaybeAge: z.number().optional(),
+  MaybeAge: z.number().int().optional(),
   MaybeHeight: z.number().optional(),
   MaybeProfile: ProfileSchema.optional(),
 })
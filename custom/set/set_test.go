@@ -16,7 +16,7 @@ func TestSet(t *testing.T) {
 	assert.Equal(t,
 		`export const UserSchema = z.object({
   Nicknames: z.string().array(),
-  FavoriteNumbers: z.number().array(),
+  FavoriteNumbers: z.number().int().array(),
 })
 export type User = z.infer<typeof UserSchema>
 
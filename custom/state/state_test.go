@@ -6,6 +6,7 @@ import (
 	"github.com/Southclaws/supervillain"
 	"github.com/Southclaws/supervillain/custom/state"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestState(t *testing.T) {
@@ -21,3 +22,22 @@ export type Job = z.infer<typeof JobSchema>
 `,
 		supervillain.StructToZodSchema(Job{}))
 }
+
+func TestStateEnum(t *testing.T) {
+	type Job struct {
+		State state.State
+	}
+	c := supervillain.NewConverter(nil)
+	require.NoError(t, c.RegisterEnum(state.StateUnknown, state.StateProcessing, state.StateSuccess, state.StateFailed))
+	assert.Equal(t,
+		`export const StateSchema = z.enum(["unknown", "processing", "success", "failed"])
+export type State = z.infer<typeof StateSchema>
+
+export const JobSchema = z.object({
+  State: StateSchema,
+})
+export type Job = z.infer<typeof JobSchema>
+
+`,
+		c.Convert(Job{}))
+}
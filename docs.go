@@ -0,0 +1,138 @@
+package supervillain
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DocSource finds the doc comments of types and their fields, which reflection
+// cannot see.
+type DocSource interface {
+	// TypeDoc gets the doc comment of a named type, or an empty string.
+	TypeDoc(t reflect.Type) string
+	// FieldDoc gets the doc comment of a field, by its Go name, declared in the
+	// named struct type t, or an empty string.
+	FieldDoc(t reflect.Type, field string) string
+}
+
+type docSourceOption struct{ DocSource }
+
+func (d docSourceOption) apply(c *Converter) {
+	c.docSource = d.DocSource
+}
+
+// WithDocSource adds the doc comments of types and fields to the output, as
+// `.describe()` on their schemas and as JSDoc comments on their declarations. A
+// `Deprecated:` paragraph becomes a `@deprecated` tag.
+func WithDocSource(s DocSource) Option {
+	return docSourceOption{s}
+}
+
+// splits a Go doc comment into its description and its deprecation notice,
+// which by convention is a paragraph starting with `Deprecated:`.
+func parseDoc(doc string) (description []string, deprecated string, isDeprecated bool) {
+	for _, paragraph := range strings.Split(strings.TrimSpace(doc), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		if strings.HasPrefix(paragraph, "Deprecated:") {
+			deprecated = strings.Join(strings.Fields(strings.TrimPrefix(paragraph, "Deprecated:")), " ")
+			isDeprecated = true
+			continue
+		}
+		description = append(description, paragraph)
+	}
+	return description, deprecated, isDeprecated
+}
+
+// builds the JSDoc comment and `.describe()` text for a doc comment. the JSDoc
+// comment keeps the lines of the original and ends with a newline, so that it
+// can be written directly before a declaration.
+func formatDoc(doc string, indent int) (string, string) {
+	description, deprecated, isDeprecated := parseDoc(doc)
+	if len(description) == 0 && !isDeprecated {
+		return "", ""
+	}
+
+	lines := []string{}
+	for i, paragraph := range description {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, strings.Split(paragraph, "\n")...)
+	}
+	if isDeprecated {
+		lines = append(lines, strings.TrimSpace("@deprecated "+deprecated))
+	}
+
+	jsdoc := strings.Builder{}
+	if len(lines) == 1 {
+		jsdoc.WriteString(fmt.Sprintf("%s/** %s */\n", indentation(indent), escapeComment(lines[0])))
+	} else {
+		jsdoc.WriteString(fmt.Sprintf("%s/**\n", indentation(indent)))
+		for _, line := range lines {
+			jsdoc.WriteString(strings.TrimRight(fmt.Sprintf("%s * %s", indentation(indent), escapeComment(line)), " "))
+			jsdoc.WriteString("\n")
+		}
+		jsdoc.WriteString(fmt.Sprintf("%s */\n", indentation(indent)))
+	}
+
+	// the description is shown as plain text, so line breaks within a
+	// paragraph are not kept.
+	paragraphs := make([]string, len(description))
+	for i, paragraph := range description {
+		paragraphs[i] = strings.Join(strings.Fields(paragraph), " ")
+	}
+
+	return jsdoc.String(), strings.Join(paragraphs, "\n\n")
+}
+
+// the `.describe()` call for a description, if there is one.
+func describeCall(text string) string {
+	if text == "" {
+		return ""
+	}
+	return fmt.Sprintf(".describe(%s)", jsString(text))
+}
+
+// stops a line of a doc comment from ending the JSDoc comment early.
+func escapeComment(s string) string {
+	return strings.ReplaceAll(s, "*/", "*\\/")
+}
+
+// gets the `.describe()` text for a doc comment, without its deprecation
+// notice.
+func docDescription(doc string) string {
+	_, describe := formatDoc(doc, 0)
+	return describe
+}
+
+// gets the doc comment of a named type.
+func (c *Converter) typeDoc(t reflect.Type) string {
+	if c.docSource == nil || t.Name() == "" {
+		return ""
+	}
+	return c.docSource.TypeDoc(t)
+}
+
+// gets the doc comment of a field of a struct.
+func (c *Converter) fieldDoc(owner reflect.Type, f reflect.StructField) string {
+	if c.docSource == nil || owner.Name() == "" {
+		return ""
+	}
+	return c.docSource.FieldDoc(owner, f.Name)
+}
+
+// gets the struct that declares a field, which for promoted fields is an
+// embedded struct rather than the struct being converted.
+func fieldOwner(t reflect.Type, index []int) reflect.Type {
+	for _, i := range index[:len(index)-1] {
+		t = t.Field(i).Type
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	return t
+}
@@ -0,0 +1,123 @@
+package supervillain
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// finds doc comments by the name of the type and, for fields, the name of the
+// field after a dot.
+type docSource map[string]string
+
+func (d docSource) TypeDoc(t reflect.Type) string {
+	return d[t.Name()]
+}
+
+func (d docSource) FieldDoc(t reflect.Type, field string) string {
+	return d[t.Name()+"."+field]
+}
+
+type Product struct {
+	Name  string
+	Price float64 `json:"price" zod:"describe=In pounds"`
+	SKU   string  `json:"sku,omitempty"`
+	ProductDetails
+}
+
+type ProductDetails struct {
+	Weight float64
+}
+
+type Reply struct {
+	Text    string
+	Replies []Reply
+}
+
+func TestDocSource(t *testing.T) {
+	source := docSource{
+		"Product":               "Product is something for sale.\n\nProducts are listed in the\ncatalogue.\n",
+		"Product.Name":          "Name is shown */ everywhere.\n",
+		"Product.Price":         "Price is not used, the tag wins.\n",
+		"Product.SKU":           "SKU is the old stock code.\n\nDeprecated: use Name instead.\n",
+		"ProductDetails.Weight": "Weight is in kilograms.\n",
+	}
+
+	c := NewConverter(nil, WithDocSource(source))
+	assert.Equal(t,
+		`export const ProductSchema = z.object({
+  /** Name is shown *\/ everywhere. */
+  Name: z.string().describe("Name is shown */ everywhere."),
+  /** Price is not used, the tag wins. */
+  price: z.number().describe("In pounds"),
+  /**
+   * SKU is the old stock code.
+   * @deprecated use Name instead.
+   */
+  sku: z.string().optional().describe("SKU is the old stock code."),
+  /** Weight is in kilograms. */
+  Weight: z.number().describe("Weight is in kilograms."),
+}).describe("Product is something for sale.\n\nProducts are listed in the catalogue.")
+/**
+ * Product is something for sale.
+ *
+ * Products are listed in the
+ * catalogue.
+ */
+export type Product = z.infer<typeof ProductSchema>
+
+`,
+		c.Convert(Product{}))
+}
+
+func TestDocSourceRecursive(t *testing.T) {
+	source := docSource{
+		"Reply":         "Reply is an answer to a post or another reply.\n",
+		"Reply.Replies": "Deprecated: replies are no longer nested.\n",
+	}
+
+	c := NewConverter(nil, WithDocSource(source))
+	assert.Equal(t,
+		`/** Reply is an answer to a post or another reply. */
+export type Reply = {
+  Text: string
+  /** @deprecated replies are no longer nested. */
+  Replies: Reply[] | null
+}
+export const ReplySchema: z.ZodType<Reply> = z.object({
+  Text: z.string(),
+  /** @deprecated replies are no longer nested. */
+  Replies: z.lazy(() => ReplySchema).array().nullable(),
+}).describe("Reply is an answer to a post or another reply.")
+
+`,
+		c.Convert(Reply{}))
+}
+
+func TestDocSourceEnum(t *testing.T) {
+	source := docSource{
+		"Colour":      "Colour is the colour of a label.\n",
+		"Task.Colour": "Colour is shown behind the title.\n",
+	}
+
+	c := NewConverter(nil, WithDocSource(source))
+	require.NoError(t, c.RegisterEnum(ColourRed, ColourGreen, ColourBlue))
+	assert.Equal(t,
+		`export const ColourSchema = z.enum(["red", "green", "blue"]).describe("Colour is the colour of a label.")
+/** Colour is the colour of a label. */
+export type Colour = z.infer<typeof ColourSchema>
+
+export const TaskSchema = z.object({
+  /** Colour is shown behind the title. */
+  Colour: ColourSchema.describe("Colour is shown behind the title."),
+  Priority: z.number().int().nullable(),
+  Visibility: z.string(),
+  labels: ColourSchema.array().optional(),
+})
+export type Task = z.infer<typeof TaskSchema>
+
+`,
+		c.Convert(Task{}))
+}
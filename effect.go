@@ -0,0 +1,361 @@
+package supervillain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EffectPrinter prints declarations as Effect schemas and the types that are
+// decoded with them. Fields with the `,string` option are decoded from strings,
+// so their encoded type differs from their decoded one.
+//
+// Zod methods from the `zod` tag have no Effect equivalent so they are left
+// out, and custom schemas from the tag are `Schema.Unknown`.
+type EffectPrinter struct {
+	// Prefix is added to the start of every declared name.
+	Prefix string
+	// Classes exports structs that are not generic as `Schema.Class`, along
+	// with a constant for their schema so they can be used like any other.
+	Classes bool
+}
+
+// Name is "effect".
+func (p EffectPrinter) Name() string {
+	return "effect"
+}
+
+// Import imports Schema from effect.
+func (p EffectPrinter) Import(Preamble) string {
+	return "import { Schema } from \"effect\"\n"
+}
+
+// Declaration prints the schema and type of a declaration, along with the
+// object of values of an enum.
+func (p EffectPrinter) Declaration(d *Declaration) string {
+	name := p.Prefix + d.Name
+	schemaName := schemaName(p.Prefix, d.Name)
+	jsdoc, _ := formatDoc(d.Doc, 0)
+
+	annotations := ""
+	if d.Description != "" {
+		annotations = fmt.Sprintf("{ description: %s }", jsString(d.Description))
+	}
+
+	output := strings.Builder{}
+	obj, isObject := d.Schema.(*Object)
+	switch {
+	case len(d.Params) > 0:
+		typeParams := make([]string, len(d.Params))
+		valueParams := make([]string, len(d.Params))
+		for i, param := range d.Params {
+			typeParams[i] = fmt.Sprintf("%s extends Schema.Schema.Any", param)
+			valueParams[i] = fmt.Sprintf("%s: %s", strings.ToLower(param), param)
+		}
+		output.WriteString(fmt.Sprintf("export const %s = <%s>(%s) => %s\n",
+			schemaName, strings.Join(typeParams, ", "), strings.Join(valueParams, ", "), effectAnnotate(p.Schema(d.Schema, 0), annotations)))
+		output.WriteString(jsdoc)
+		output.WriteString(fmt.Sprintf("export type %s<%s> = Schema.Schema.Type<ReturnType<typeof %s<%s>>>",
+			name, strings.Join(typeParams, ", "), schemaName, strings.Join(d.Params, ", ")))
+
+	case p.Classes && isObject:
+		// the class is its own type, so it can refer to itself without one
+		// being written out by hand.
+		args := []string{p.fields(obj, 0)}
+		if annotations != "" {
+			args = append(args, annotations)
+		}
+		output.WriteString(jsdoc)
+		output.WriteString(fmt.Sprintf("export class %s extends Schema.Class<%s>(%s)(%s) {}\n",
+			name, name, jsString(name), strings.Join(args, ", ")))
+		output.WriteString(fmt.Sprintf("export const %s = %s", schemaName, name))
+
+	case d.Recursive:
+		// the types cannot be inferred for schemas that refer to themselves so
+		// they are written out by hand and the schema is annotated with them.
+		// the encoded type is only needed where it differs, which is where a
+		// field is decoded from a string.
+		types := typePrinter{prefix: p.Prefix, argType: "Schema.Schema<%s>", readonly: true}
+		decoded := p.Type(d.Schema, 0)
+
+		output.WriteString(jsdoc)
+		output.WriteString(fmt.Sprintf("export type %s = %s\n", name, decoded))
+		typeArgs := name
+		if types.typ(d.Schema, 0) != decoded {
+			types.names = map[string]string{d.Key: name + "Encoded"}
+			output.WriteString(fmt.Sprintf("export type %sEncoded = %s\n", name, types.typ(d.Schema, 0)))
+			typeArgs += ", " + name + "Encoded"
+		}
+		output.WriteString(fmt.Sprintf("export const %s: Schema.Schema<%s> = %s",
+			schemaName, typeArgs, effectAnnotate(p.Schema(d.Schema, 0), annotations)))
+
+	default:
+		output.WriteString(fmt.Sprintf("export const %s = %s\n", schemaName, effectAnnotate(p.Schema(d.Schema, 0), annotations)))
+		output.WriteString(jsdoc)
+		output.WriteString(fmt.Sprintf("export type %s = typeof %s.Type", name, schemaName))
+	}
+
+	output.WriteString(enumObject(name, d.Schema))
+
+	return output.String()
+}
+
+// Schema prints a schema as an Effect expression. Objects are indented by the
+// given level, as they would be when written inside other objects at it.
+func (p EffectPrinter) Schema(s Schema, indent int) string {
+	switch s := s.(type) {
+	case *Primitive:
+		return p.primitive(s)
+
+	case *Const:
+		return effectLiteral(s.Value)
+
+	case *Enum:
+		return effectLiteral(s.Values...)
+
+	case *Object:
+		return fmt.Sprintf("Schema.Struct(%s)", p.fields(s, indent))
+
+	case *Array:
+		filters := []string{}
+		for _, check := range s.Checks {
+			switch check.Kind {
+			case CheckMin:
+				filters = append(filters, fmt.Sprintf("Schema.minItems(%s)", check.Value))
+			case CheckMax:
+				filters = append(filters, fmt.Sprintf("Schema.maxItems(%s)", check.Value))
+			case CheckLength:
+				filters = append(filters, fmt.Sprintf("Schema.itemsCount(%s)", check.Value))
+			}
+		}
+		return effectPipe(fmt.Sprintf("Schema.Array(%s)", p.Schema(s.Items, indent)), filters)
+
+	case *Tuple:
+		items := make([]string, len(s.Items))
+		for i, item := range s.Items {
+			items[i] = p.Schema(item, indent)
+		}
+		return fmt.Sprintf("Schema.Tuple(%s)", strings.Join(items, ", "))
+
+	case *Record:
+		return fmt.Sprintf("Schema.Record({ key: %s, value: %s })", p.Schema(s.Key, indent), p.Schema(s.Value, indent))
+
+	case *Union:
+		members := make([]string, len(s.Members))
+		for i, m := range s.Members {
+			members[i] = p.Schema(m, indent)
+		}
+		if len(members) == 1 {
+			return members[0]
+		}
+		return fmt.Sprintf("Schema.Union(%s)", strings.Join(members, ", "))
+
+	case *OrEmpty:
+		return fmt.Sprintf("Schema.Union(%s, %s)", p.Schema(s.Schema, indent), effectLiteral(s.Value))
+
+	case *Reference:
+		name := schemaName(p.Prefix, s.Name)
+		if s.Lazy {
+			if p.Classes {
+				// a class cannot be inferred from a schema that refers back to
+				// it, so the reference gives its type.
+				return fmt.Sprintf("Schema.suspend((): Schema.Schema<%s> => %s)", p.Prefix+s.Name, name)
+			}
+			return fmt.Sprintf("Schema.suspend(() => %s)", name)
+		}
+		if len(s.Args) > 0 {
+			args := make([]string, len(s.Args))
+			for i, arg := range s.Args {
+				args[i] = p.Schema(arg, indent)
+			}
+			return fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+		}
+		return name
+
+	case *Param:
+		return strings.ToLower(s.Name)
+
+	case *Custom:
+		if schema, ok := s.Libraries[p.Name()]; ok {
+			return schema
+		}
+	}
+
+	return "Schema.Unknown"
+}
+
+// prints the fields of an object as the argument of Schema.Struct or
+// Schema.Class.
+func (p EffectPrinter) fields(s *Object, indent int) string {
+	output := strings.Builder{}
+	output.WriteString("{\n")
+	for _, f := range s.Fields {
+		output.WriteString(p.field(f, indent+1))
+	}
+	output.WriteString(indentation(indent))
+	output.WriteString("}")
+	return output.String()
+}
+
+func (p EffectPrinter) field(f *Field, indent int) string {
+	jsdoc, _ := formatDoc(f.Doc, indent)
+
+	schema := p.Schema(f.Schema, indent)
+	if f.Description != "" {
+		schema = effectAnnotate(schema, fmt.Sprintf("{ description: %s }", jsString(f.Description)))
+	}
+	if f.Nullable || f.Nullish {
+		schema = fmt.Sprintf("Schema.NullOr(%s)", schema)
+	}
+	switch {
+	case f.Default != "":
+		schema = fmt.Sprintf("Schema.optionalWith(%s, { default: () => %s })", schema, f.Default)
+	case f.Optional || f.Nullish:
+		schema = fmt.Sprintf("Schema.optional(%s)", schema)
+	}
+
+	return fmt.Sprintf("%s%s%s: %s,\n", jsdoc, indentation(indent), f.Name, schema)
+}
+
+func (p EffectPrinter) primitive(s *Primitive) string {
+	schema := ""
+	switch s.Kind {
+	case PrimitiveString:
+		schema = "Schema.String"
+	case PrimitiveNumber:
+		schema = "Schema.Number"
+		if s.Coerce {
+			schema = "Schema.NumberFromString"
+		}
+	case PrimitiveBoolean:
+		schema = "Schema.Boolean"
+		if s.Coerce {
+			// the value is one of two strings, decoded to whether it is "true".
+			schema = `Schema.transform(Schema.Literal("true", "false"), Schema.Boolean, { strict: true, decode: (s) => s === "true", encode: (b) => (b ? "true" : "false") })`
+		}
+	case PrimitiveBigInt:
+		schema = "Schema.BigIntFromSelf"
+		if s.Coerce {
+			schema = "Schema.BigInt"
+		}
+	case PrimitiveAny:
+		schema = "Schema.Any"
+	default:
+		schema = "Schema.Unknown"
+	}
+
+	filters := []string{}
+	for _, check := range s.Checks {
+		if check.Kind == CheckTrue {
+			return effectLiteral("true")
+		}
+		filters = append(filters, p.filter(check, s.Kind))
+	}
+
+	return effectPipe(schema, filters)
+}
+
+// the Effect filters for bounds of a number, which take the value of the
+// check. Bigints use the same filters with a BigInt suffix.
+var effectNumberFilters = map[CheckKind]string{
+	CheckMin:                "Schema.greaterThanOrEqualTo",
+	CheckMax:                "Schema.lessThanOrEqualTo",
+	CheckGreaterThan:        "Schema.greaterThan",
+	CheckGreaterThanOrEqual: "Schema.greaterThanOrEqualTo",
+	CheckLessThan:           "Schema.lessThan",
+	CheckLessThanOrEqual:    "Schema.lessThanOrEqualTo",
+}
+
+// the Effect filters for the other kinds of check, with the value of the
+// check. Effect has no filters for emails, URLs or UUIDs so they are patterns
+// and refinements.
+var effectFilters = map[CheckKind]string{
+	CheckInt:       "Schema.int()",
+	CheckMin:       "Schema.minLength(%s)",
+	CheckMax:       "Schema.maxLength(%s)",
+	CheckLength:    "Schema.length(%s)",
+	CheckRegex:     "Schema.pattern(/%s/)",
+	CheckEmail:     `Schema.pattern(/^[^\s@]+@[^\s@]+\.[^\s@]+$/)`,
+	CheckURL:       "Schema.filter((s) => URL.canParse(s))",
+	CheckUUID:      "Schema.pattern(/^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$/i)",
+	CheckLowercase: "Schema.lowercased()",
+	CheckUppercase: "Schema.uppercased()",
+}
+
+// patterns for IP addresses, which Effect has no filter for either.
+const (
+	effectIPv4 = `/^((25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(25[0-5]|2[0-4]\d|1?\d?\d)$/`
+	effectIPv6 = `/^(([0-9a-f]{1,4}:){7}[0-9a-f]{1,4}|(([0-9a-f]{1,4}:)*[0-9a-f]{1,4})?::(([0-9a-f]{1,4}:)*[0-9a-f]{1,4})?)$/i`
+)
+
+// prints a check on a primitive of the given kind as an Effect filter.
+func (p EffectPrinter) filter(check Check, kind PrimitiveKind) string {
+	bigint := kind == PrimitiveBigInt
+	if fn, ok := effectNumberFilters[check.Kind]; ok && kind != PrimitiveString {
+		if bigint {
+			return fmt.Sprintf("%sBigInt(%sn)", fn, check.Value)
+		}
+		return fmt.Sprintf("%s(%s)", fn, check.Value)
+	}
+
+	switch check.Kind {
+	case CheckNonNegative:
+		if bigint {
+			return "Schema.nonNegativeBigInt()"
+		}
+		return "Schema.nonNegative()"
+	case CheckNonZero:
+		if bigint {
+			return "Schema.filter((n) => n !== 0n)"
+		}
+		return "Schema.filter((n) => n !== 0)"
+	case CheckIP:
+		switch check.Value {
+		case "v4":
+			return fmt.Sprintf("Schema.pattern(%s)", effectIPv4)
+		case "v6":
+			return fmt.Sprintf("Schema.pattern(%s)", effectIPv6)
+		}
+		return fmt.Sprintf("Schema.filter((s) => %s.test(s) || %s.test(s))", effectIPv4, effectIPv6)
+	case CheckStartsWith:
+		return fmt.Sprintf("Schema.startsWith(%s)", jsString(check.Value))
+	case CheckEndsWith:
+		return fmt.Sprintf("Schema.endsWith(%s)", jsString(check.Value))
+	case CheckIncludes:
+		return fmt.Sprintf("Schema.includes(%s)", jsString(check.Value))
+	}
+
+	filter := effectFilters[check.Kind]
+	if strings.Contains(filter, "%s") {
+		return fmt.Sprintf(filter, check.Value)
+	}
+	return filter
+}
+
+// Type prints the TypeScript type that a schema decodes to, which is written
+// out by hand for declarations that refer to themselves.
+func (p EffectPrinter) Type(s Schema, indent int) string {
+	return typePrinter{prefix: p.Prefix, parsed: true, argType: "Schema.Schema<%s>", readonly: true}.typ(s, indent)
+}
+
+// pipes the schema through the filters, if there are any.
+func effectPipe(schema string, filters []string) string {
+	if len(filters) == 0 {
+		return schema
+	}
+	return fmt.Sprintf("%s.pipe(%s)", schema, strings.Join(filters, ", "))
+}
+
+// adds annotations, such as a description, to a schema if there are any.
+func effectAnnotate(schema, annotations string) string {
+	if annotations == "" {
+		return schema
+	}
+	return fmt.Sprintf("%s.annotations(%s)", schema, annotations)
+}
+
+func effectLiteral(values ...string) string {
+	if len(values) == 1 && values[0] == "null" {
+		return "Schema.Null"
+	}
+	return fmt.Sprintf("Schema.Literal(%s)", strings.Join(values, ", "))
+}
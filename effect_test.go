@@ -0,0 +1,88 @@
+package supervillain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Author struct {
+	Name  string
+	Books []Book
+}
+
+type Book struct {
+	Title  string
+	Author *Author
+}
+
+type Shelf struct {
+	Books   []Book
+	Curator Author
+}
+
+func TestConvertEffectClasses(t *testing.T) {
+	c := NewConverter(nil)
+	assert.Equal(t, `export class Author extends Schema.Class<Author>("Author")({
+  Name: Schema.String,
+  Books: Schema.NullOr(Schema.Array(Schema.suspend((): Schema.Schema<Book> => BookSchema))),
+}) {}
+export const AuthorSchema = Author
+
+export class Book extends Schema.Class<Book>("Book")({
+  Title: Schema.String,
+  Author: Schema.NullOr(AuthorSchema),
+}) {}
+export const BookSchema = Book
+
+export class Shelf extends Schema.Class<Shelf>("Shelf")({
+  Books: Schema.NullOr(Schema.Array(BookSchema)),
+  Curator: AuthorSchema,
+}) {}
+export const ShelfSchema = Shelf
+
+`, c.ConvertLibrary(EffectPrinter{Classes: true}, []interface{}{Shelf{}}))
+}
+
+func TestConvertEffectEncoded(t *testing.T) {
+	type Counter struct {
+		Count   int      `json:"count,string"`
+		Enabled bool     `json:"enabled,string"`
+		Email   string   `json:"email" validate:"email,lowercase"`
+		Label   string   `json:"label" zod:"default=\"none\",describe=Shown to users"`
+		Note    string   `json:"note" zod:"nullish"`
+		Next    *Counter `json:"next"`
+	}
+
+	// the encoded type is written out too as it differs where fields are
+	// decoded from strings.
+	c := NewConverter(nil, WithValidationTags("validate"), WithQuotedMode(QuotedCoerce), WithPreamble(Preamble{}))
+	assert.Equal(t, `import { Schema } from "effect"
+
+export type Counter = {
+  readonly count: number
+  readonly enabled: boolean
+  readonly email: string
+  readonly label: string
+  readonly note?: string | null
+  readonly next: Counter | null
+}
+export type CounterEncoded = {
+  readonly count: string
+  readonly enabled: "true" | "false"
+  readonly email: string
+  readonly label: string
+  readonly note?: string | null
+  readonly next: CounterEncoded | null
+}
+export const CounterSchema: Schema.Schema<Counter, CounterEncoded> = Schema.Struct({
+  count: Schema.NumberFromString.pipe(Schema.int()),
+  enabled: Schema.transform(Schema.Literal("true", "false"), Schema.Boolean, { strict: true, decode: (s) => s === "true", encode: (b) => (b ? "true" : "false") }),
+  email: Schema.String.pipe(Schema.pattern(/^[^\s@]+@[^\s@]+\.[^\s@]+$/), Schema.lowercased()),
+  label: Schema.optionalWith(Schema.String.annotations({ description: "Shown to users" }), { default: () => "none" }),
+  note: Schema.optional(Schema.NullOr(Schema.String)),
+  next: Schema.NullOr(Schema.suspend(() => CounterSchema)),
+})
+
+`, c.ConvertLibrary(EffectPrinter{}, []interface{}{Counter{}}))
+}
@@ -0,0 +1,247 @@
+package supervillain
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidEnum is reported by RegisterEnum for values that cannot be used as
+// the members of an enum.
+const ErrInvalidEnum ErrorCode = "invalid_enum"
+
+// EnumValue is a member of an enum type, along with the name of the constant it
+// is declared as.
+type EnumValue struct {
+	// Name is the name of the Go constant, such as `StateProcessing`. When every
+	// value of an enum is named, a TypeScript object of the values is declared
+	// alongside the schema.
+	Name string
+	// Value is the value of the constant, which must be of the enum type.
+	Value any
+	// JSON is the JSON encoding of the value when it is not written by
+	// marshalling Value, such as the quoted result of a String method.
+	JSON string
+}
+
+// EnumSource finds the values of enum types that have not been registered with
+// RegisterEnum. It is asked about each named type that is not a struct the
+// first time it is found.
+type EnumSource interface {
+	EnumValues(t reflect.Type) ([]EnumValue, bool)
+}
+
+type enumSourceOption struct{ EnumSource }
+
+func (e enumSourceOption) apply(c *Converter) {
+	c.enumSource = e.EnumSource
+}
+
+// WithEnumSource sets where the values of enum types that have not been
+// registered are found, such as from the constants in the source code.
+func WithEnumSource(s EnumSource) Option {
+	return enumSourceOption{s}
+}
+
+type enum struct {
+	typ reflect.Type
+	// the JSON encoding of each distinct value, in the order they were
+	// registered.
+	values []string
+	// the key and JSON encoding of every value for the object of values, only
+	// set when every value is named.
+	keys  []string
+	keyed []string
+}
+
+// RegisterEnum registers every value of an enum type. Each value is marshalled
+// to JSON and, wherever the type is found, it is converted to a schema named
+// after the type that only accepts those values. This takes precedence over any
+// ZodSchema method or custom function for the type.
+//
+// All values must be of the same named type and marshal to strings, numbers,
+// booleans or null. Registering the same type again replaces its values.
+func (c *Converter) RegisterEnum(values ...any) error {
+	named := make([]EnumValue, len(values))
+	for i, v := range values {
+		named[i] = EnumValue{Value: v}
+	}
+	return c.RegisterEnumValues(named...)
+}
+
+// RegisterEnumValues is like RegisterEnum, but when every value is named a
+// TypeScript object mapping the names to the values is also declared. The name
+// of the type is removed from the start of each name, so `StateProcessing`
+// becomes `State.Processing`.
+func (c *Converter) RegisterEnumValues(values ...EnumValue) error {
+	e, err := newEnum(values)
+	if err != nil {
+		return err
+	}
+
+	if c.enums == nil {
+		c.enums = map[string]enum{}
+	}
+	c.enums[typeKey(e.typ)] = e
+
+	return nil
+}
+
+func newEnum(values []EnumValue) (enum, *ConversionError) {
+	if len(values) == 0 {
+		return enum{}, &ConversionError{Code: ErrInvalidEnum, Message: "no values given"}
+	}
+
+	t := reflect.TypeOf(values[0].Value)
+	if t == nil || t.Name() == "" || t.PkgPath() == "" {
+		return enum{}, &ConversionError{Type: t, Code: ErrInvalidEnum, Message: fmt.Sprintf("enum values must be of a declared type, got %v", t)}
+	}
+
+	e := enum{typ: t}
+	seen := map[string]bool{}
+	for _, v := range values {
+		if vt := reflect.TypeOf(v.Value); vt != t {
+			return enum{}, &ConversionError{Path: t.Name(), Type: vt, Code: ErrInvalidEnum, Message: fmt.Sprintf("enum values must all be %s, got %v", t, vt)}
+		}
+
+		b := []byte(v.JSON)
+		if v.JSON == "" {
+			var err error
+			b, err = json.Marshal(v.Value)
+			if err != nil {
+				return enum{}, &ConversionError{Path: t.Name(), Type: t, Code: ErrInvalidEnum, Message: fmt.Sprintf("cannot marshal %v: %v", v.Value, err)}
+			}
+		}
+
+		var literal any
+		if err := json.Unmarshal(b, &literal); err != nil {
+			return enum{}, &ConversionError{Path: t.Name(), Type: t, Code: ErrInvalidEnum, Message: fmt.Sprintf("cannot unmarshal %s: %v", b, err)}
+		}
+		switch literal.(type) {
+		case string, float64, bool, nil:
+		default:
+			return enum{}, &ConversionError{Path: t.Name(), Type: t, Code: ErrInvalidEnum, Message: fmt.Sprintf("%v marshals to %s, which is not a literal", v.Value, b)}
+		}
+
+		if !seen[string(b)] {
+			seen[string(b)] = true
+			e.values = append(e.values, string(b))
+		}
+
+		if v.Name != "" {
+			e.keys = append(e.keys, enumKey(t.Name(), v.Name))
+			e.keyed = append(e.keyed, string(b))
+		}
+	}
+
+	if len(e.keys) != len(values) {
+		e.keys, e.keyed = nil, nil
+	}
+
+	return e, nil
+}
+
+// gets the registered enum for a type, asking the enum source about types that
+// have not been seen before.
+func (c *Converter) enumFor(t reflect.Type) (enum, bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Name() == "" || t.PkgPath() == "" || t.Kind() == reflect.Struct {
+		return enum{}, false
+	}
+
+	key := typeKey(t)
+	if e, ok := c.enums[key]; ok {
+		return e, e.typ == t
+	}
+
+	if c.enumSource == nil || c.enumsChecked[key] {
+		return enum{}, false
+	}
+	if c.enumsChecked == nil {
+		c.enumsChecked = map[string]bool{}
+	}
+	c.enumsChecked[key] = true
+
+	values, ok := c.enumSource.EnumValues(t)
+	if !ok {
+		return enum{}, false
+	}
+
+	e, err := newEnum(values)
+	if err != nil {
+		c.fail(t, err.Code, err.Message)
+		return enum{}, false
+	}
+
+	if c.enums == nil {
+		c.enums = map[string]enum{}
+	}
+	c.enums[key] = e
+
+	return e, e.typ == t
+}
+
+func (c *Converter) isEnum(t reflect.Type) bool {
+	_, ok := c.enumFor(t)
+	return ok
+}
+
+// converts a registered enum type to a reference to its schema, declaring the
+// schema if this is the first time the type is found.
+func (c *Converter) convertEnum(t reflect.Type) Schema {
+	key := typeKey(t)
+	name := c.nameFor(t)
+	if _, ok := c.outputs[key]; !ok {
+		e := c.enums[key]
+
+		schema := &Enum{Values: e.values}
+		for i := range e.keys {
+			schema.Constants = append(schema.Constants, EnumConstant{Name: e.keys[i], Value: e.keyed[i]})
+		}
+
+		c.addSchema(c.declare(t, key, name, schema, false))
+	}
+	c.addDependency(key)
+	return &Reference{Key: key, Name: name}
+}
+
+// builds a z.enum when every value is a string, and a union of literals
+// otherwise because z.enum only accepts strings.
+func enumSchema(values []string) string {
+	strs := true
+	for _, v := range values {
+		if !strings.HasPrefix(v, `"`) {
+			strs = false
+		}
+	}
+	if strs {
+		return fmt.Sprintf("z.enum([%s])", strings.Join(values, ", "))
+	}
+
+	literals := make([]string, len(values))
+	for i, v := range values {
+		if v == "null" {
+			literals[i] = "z.null()"
+		} else {
+			literals[i] = fmt.Sprintf("z.literal(%s)", v)
+		}
+	}
+	if len(literals) == 1 {
+		return literals[0]
+	}
+	return fmt.Sprintf("z.union([%s])", strings.Join(literals, ", "))
+}
+
+// gets the key for a named enum value by removing the type name from the start
+// of the constant name, as long as what remains is still an identifier.
+func enumKey(typ, name string) string {
+	key := strings.TrimLeft(strings.TrimPrefix(name, typ), "_")
+	if key == "" || !unicode.IsLetter([]rune(key)[0]) {
+		return name
+	}
+	return key
+}
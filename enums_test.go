@@ -0,0 +1,254 @@
+package supervillain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Colour string
+
+const (
+	ColourRed   Colour = "red"
+	ColourGreen Colour = "green"
+	ColourBlue  Colour = "blue"
+)
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityMedium
+	PriorityHigh
+)
+
+type Visibility int
+
+func (v Visibility) MarshalJSON() ([]byte, error) {
+	if v == 0 {
+		return []byte(`"private"`), nil
+	}
+	return []byte(`"public"`), nil
+}
+
+func (v Visibility) ZodSchema() string {
+	return "z.string()"
+}
+
+type Task struct {
+	Colour     Colour
+	Priority   *Priority
+	Visibility Visibility
+	Labels     []Colour `json:"labels,omitempty"`
+}
+
+func TestRegisterEnum(t *testing.T) {
+	c := NewConverter(nil)
+	require.NoError(t, c.RegisterEnum(ColourRed, ColourGreen, ColourBlue))
+	require.NoError(t, c.RegisterEnum(PriorityLow, PriorityMedium, PriorityHigh))
+	require.NoError(t, c.RegisterEnum(Visibility(0), Visibility(1), Visibility(2)))
+
+	assert.Equal(t,
+		`export const ColourSchema = z.enum(["red", "green", "blue"])
+export type Colour = z.infer<typeof ColourSchema>
+
+export const PrioritySchema = z.union([z.literal(0), z.literal(1), z.literal(2)])
+export type Priority = z.infer<typeof PrioritySchema>
+
+export const VisibilitySchema = z.enum(["private", "public"])
+export type Visibility = z.infer<typeof VisibilitySchema>
+
+export const TaskSchema = z.object({
+  Colour: ColourSchema,
+  Priority: PrioritySchema.nullable(),
+  Visibility: VisibilitySchema,
+  labels: ColourSchema.array().optional(),
+})
+export type Task = z.infer<typeof TaskSchema>
+
+`,
+		c.Convert(Task{}))
+}
+
+func TestRegisterEnumUnregistered(t *testing.T) {
+	assert.Equal(t,
+		`export const TaskSchema = z.object({
+  Colour: z.string(),
+  Priority: z.number().int().nullable(),
+  Visibility: z.string(),
+  labels: z.string().array().optional(),
+})
+export type Task = z.infer<typeof TaskSchema>
+
+`,
+		StructToZodSchema(Task{}))
+}
+
+type Node struct {
+	Colour   Colour
+	Children []Node
+}
+
+func TestRegisterEnumRecursive(t *testing.T) {
+	c := NewConverter(nil)
+	require.NoError(t, c.RegisterEnum(ColourRed, ColourGreen))
+
+	assert.Equal(t,
+		`export const ColourSchema = z.enum(["red", "green"])
+export type Colour = z.infer<typeof ColourSchema>
+
+export type Node = {
+  Colour: Colour
+  Children: Node[] | null
+}
+export const NodeSchema: z.ZodType<Node> = z.object({
+  Colour: ColourSchema,
+  Children: z.lazy(() => NodeSchema).array().nullable(),
+})
+
+`,
+		c.Convert(Node{}))
+}
+
+func TestRegisterEnumInvalid(t *testing.T) {
+	c := NewConverter(nil)
+
+	var cerr *ConversionError
+	err := c.RegisterEnum()
+	require.True(t, errors.As(err, &cerr))
+	assert.Equal(t, ErrInvalidEnum, cerr.Code)
+
+	err = c.RegisterEnum("red", "green")
+	require.True(t, errors.As(err, &cerr))
+	assert.Equal(t, ErrInvalidEnum, cerr.Code)
+
+	err = c.RegisterEnum(ColourRed, PriorityLow)
+	require.True(t, errors.As(err, &cerr))
+	assert.Equal(t, "Colour: enum values must all be supervillain.Colour, got supervillain.Priority (invalid_enum)", err.Error())
+
+	err = c.RegisterEnum(Task{})
+	require.True(t, errors.As(err, &cerr))
+	assert.Equal(t, ErrInvalidEnum, cerr.Code)
+}
+
+func TestRegisterEnumValues(t *testing.T) {
+	type Swatch struct {
+		Colour Colour
+	}
+
+	c := NewConverter(nil)
+	require.NoError(t, c.RegisterEnumValues(
+		EnumValue{Name: "ColourRed", Value: ColourRed},
+		EnumValue{Name: "Colour_Green", Value: ColourGreen},
+		EnumValue{Name: "Colour2", Value: ColourBlue},
+	))
+
+	assert.Equal(t,
+		`export const ColourSchema = z.enum(["red", "green", "blue"])
+export type Colour = z.infer<typeof ColourSchema>
+export const Colour = {
+  Red: "red",
+  Green: "green",
+  Colour2: "blue",
+} as const
+
+export const SwatchSchema = z.object({
+  Colour: ColourSchema,
+})
+export type Swatch = z.infer<typeof SwatchSchema>
+
+`,
+		c.Convert(Swatch{}))
+}
+
+func TestRegisterEnumValuesJSON(t *testing.T) {
+	type Rank int
+	type Player struct {
+		Rank Rank
+	}
+
+	// the JSON is used in place of marshalling the value, such as the string
+	// from a String method.
+	c := NewConverter(nil)
+	require.NoError(t, c.RegisterEnumValues(
+		EnumValue{Name: "RankBronze", Value: Rank(0), JSON: `"bronze"`},
+		EnumValue{Name: "RankGold", Value: Rank(1), JSON: `"gold"`},
+	))
+
+	assert.Equal(t,
+		`export const RankSchema = z.enum(["bronze", "gold"])
+export type Rank = z.infer<typeof RankSchema>
+export const Rank = {
+  Bronze: "bronze",
+  Gold: "gold",
+} as const
+
+export const PlayerSchema = z.object({
+  Rank: RankSchema,
+})
+export type Player = z.infer<typeof PlayerSchema>
+
+`,
+		c.Convert(Player{}))
+}
+
+type enumSource map[reflect.Type][]EnumValue
+
+func (e enumSource) EnumValues(t reflect.Type) ([]EnumValue, bool) {
+	values, ok := e[t]
+	return values, ok
+}
+
+func TestEnumSource(t *testing.T) {
+	source := enumSource{
+		reflect.TypeOf(PriorityLow): {
+			{Name: "PriorityLow", Value: PriorityLow},
+			{Name: "PriorityHigh", Value: PriorityHigh},
+		},
+	}
+
+	c := NewConverter(nil, WithEnumSource(source))
+	require.NoError(t, c.RegisterEnum(ColourRed))
+
+	assert.Equal(t,
+		`export const ColourSchema = z.enum(["red"])
+export type Colour = z.infer<typeof ColourSchema>
+
+export const PrioritySchema = z.union([z.literal(0), z.literal(2)])
+export type Priority = z.infer<typeof PrioritySchema>
+export const Priority = {
+  Low: 0,
+  High: 2,
+} as const
+
+export const TaskSchema = z.object({
+  Colour: ColourSchema,
+  Priority: PrioritySchema.nullable(),
+  Visibility: z.string(),
+  labels: ColourSchema.array().optional(),
+})
+export type Task = z.infer<typeof TaskSchema>
+
+`,
+		c.Convert(Task{}))
+}
+
+func TestEnumSourceInvalid(t *testing.T) {
+	source := enumSource{
+		reflect.TypeOf(Visibility(0)): {
+			{Name: "VisibilityPrivate", Value: Visibility(0)},
+			{Name: "VisibilityPublic", Value: "public"},
+		},
+	}
+
+	c := NewConverter(nil, WithEnumSource(source))
+	_, err := c.ConvertE(Task{})
+
+	var errs ConversionErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "Task.Visibility: enum values must all be supervillain.Visibility, got string (invalid_enum)", errs[0].Error())
+}
@@ -0,0 +1,189 @@
+package supervillain
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+)
+
+// ErrorCode identifies the reason a type could not be converted.
+type ErrorCode string
+
+const (
+	// ErrUnsupportedType is reported for kinds that have no JSON representation,
+	// such as channels and functions.
+	ErrUnsupportedType ErrorCode = "unsupported_type"
+	// ErrInvalidSchemaMethod is reported for types with a ZodSchema method that
+	// does not match any of the supported signatures.
+	ErrInvalidSchemaMethod ErrorCode = "invalid_schema_method"
+	// ErrMissingCustomSchema is reported by WithStrictCustomSchemas for types
+	// with custom JSON marshalling but no custom schema.
+	ErrMissingCustomSchema ErrorCode = "missing_custom_schema"
+)
+
+// ConversionError describes a single problem found while converting a type.
+type ConversionError struct {
+	// Path is the Go field path from the top level type to the problem, for
+	// example `User.Posts[].Author.Avatar`.
+	Path string
+	// Type is the type that could not be converted.
+	Type reflect.Type
+	// Code is the reason the type could not be converted.
+	Code ErrorCode
+	// Message is a human readable description of the problem.
+	Message string
+}
+
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", e.Path, e.Message, e.Code)
+}
+
+// ConversionErrors is every problem found during a single conversion.
+type ConversionErrors []*ConversionError
+
+func (e ConversionErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (e ConversionErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// ConvertE is like Convert but returns every problem found in the type as a
+// ConversionErrors instead of panicking on the first one.
+func (c *Converter) ConvertE(input interface{}) (string, error) {
+	return c.collectErrors(func() string {
+		return c.Convert(input)
+	})
+}
+
+// ConvertSliceE is like ConvertSlice but returns every problem found in the
+// types as a ConversionErrors instead of panicking on the first one.
+func (c *Converter) ConvertSliceE(inputs []interface{}) (string, error) {
+	return c.collectErrors(func() string {
+		return c.ConvertSlice(inputs)
+	})
+}
+
+func (c *Converter) collectErrors(convert func() string) (string, error) {
+	c.collect = true
+	defer func() {
+		c.collect = false
+		c.errs = nil
+	}()
+
+	saved := c.saveState()
+	output := convert()
+	if len(c.errs) > 0 {
+		c.restoreState(saved)
+		return "", c.errs
+	}
+
+	return output, nil
+}
+
+// the state that converting types builds up. a conversion that fails puts it
+// back as it was, so the schemas it left half done with placeholders in them
+// are not returned by later conversions with the same converter.
+type conversionState struct {
+	outputs      map[string]entry
+	recursive    map[string]bool
+	deps         map[string]map[string]bool
+	names        map[string]string
+	owners       map[string]string
+	types        map[string]reflect.Type
+	refs         map[string]map[string]reference
+	enums        map[string]enum
+	enumsChecked map[string]bool
+	diagnostics  ConversionErrors
+	// generic instantiations are only checked once, so one that failed must
+	// be checked again.
+	genericUses map[string]map[string][]string
+	genericFits map[reflect.Type]bool
+}
+
+func (c *Converter) saveState() conversionState {
+	s := conversionState{
+		outputs:      maps.Clone(c.outputs),
+		recursive:    maps.Clone(c.recursive),
+		deps:         cloneNested(c.deps),
+		names:        maps.Clone(c.names),
+		owners:       maps.Clone(c.owners),
+		types:        maps.Clone(c.types),
+		refs:         cloneNested(c.refs),
+		enums:        maps.Clone(c.enums),
+		enumsChecked: maps.Clone(c.enumsChecked),
+		diagnostics:  c.diagnostics[:len(c.diagnostics):len(c.diagnostics)],
+
+		genericUses: maps.Clone(c.genericUses),
+		genericFits: map[reflect.Type]bool{},
+	}
+	for t, fits := range c.genericFits {
+		s.genericFits[t] = fits
+	}
+	return s
+}
+
+func (c *Converter) restoreState(s conversionState) {
+	c.outputs = s.outputs
+	c.recursive = s.recursive
+	c.deps = s.deps
+	c.names = s.names
+	c.owners = s.owners
+	c.types = s.types
+	c.refs = s.refs
+	c.enums = s.enums
+	c.enumsChecked = s.enumsChecked
+	c.diagnostics = s.diagnostics
+	c.genericUses = s.genericUses
+	c.genericFits = s.genericFits
+}
+
+func cloneNested[K, K2 comparable, V any](m map[K]map[K2]V) map[K]map[K2]V {
+	if m == nil {
+		return nil
+	}
+	clone := make(map[K]map[K2]V, len(m))
+	for k, inner := range m {
+		clone[k] = maps.Clone(inner)
+	}
+	return clone
+}
+
+// reports a problem with the type at the current path. unless errors are being
+// collected this panics, as conversion always has.
+func (c *Converter) fail(t reflect.Type, code ErrorCode, message string) {
+	err := &ConversionError{
+		Path:    c.currentPath(),
+		Type:    t,
+		Code:    code,
+		Message: message,
+	}
+
+	if !c.collect {
+		panic(err)
+	}
+
+	c.errs = append(c.errs, err)
+}
+
+func (c *Converter) pushPath(segment string) {
+	c.path = append(c.path, segment)
+}
+
+func (c *Converter) popPath() {
+	c.path = c.path[:len(c.path)-1]
+}
+
+func (c *Converter) currentPath() string {
+	return strings.Join(c.path, "")
+}
@@ -0,0 +1,166 @@
+package supervillain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertEUnsupportedType(t *testing.T) {
+	type Avatar struct {
+		Updates chan string
+	}
+	type Author struct {
+		Avatar Avatar
+	}
+	type Post struct {
+		Author Author
+	}
+	type User struct {
+		Posts []Post
+	}
+
+	c := NewConverter(map[string]CustomFn{})
+	output, err := c.ConvertE(User{})
+	assert.Empty(t, output)
+
+	var errs ConversionErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "User.Posts[].Author.Avatar.Updates", errs[0].Path)
+	assert.Equal(t, reflect.TypeOf(make(chan string)), errs[0].Type)
+	assert.Equal(t, ErrUnsupportedType, errs[0].Code)
+	assert.Equal(t, "User.Posts[].Author.Avatar.Updates: cannot handle: chan (unsupported_type)", err.Error())
+}
+
+func TestConvertECollectsAllErrors(t *testing.T) {
+	type Job struct {
+		State    StateWithoutSchema
+		Strange  Strange
+		Callback func()
+		Lookup   map[string]chan int
+		Embedded struct {
+			Numbers []complex64
+			Channel chan bool
+		} `json:",inline"`
+	}
+
+	c := NewConverter(map[string]CustomFn{}, WithStrictCustomSchemas(true))
+	_, err := c.ConvertE(Job{})
+
+	var errs ConversionErrors
+	require.True(t, errors.As(err, &errs))
+
+	type problem struct {
+		Path string
+		Code ErrorCode
+	}
+	problems := []problem{}
+	for _, e := range errs {
+		problems = append(problems, problem{e.Path, e.Code})
+	}
+	assert.Equal(t, []problem{
+		{"Job.State", ErrMissingCustomSchema},
+		{"Job.Strange", ErrInvalidSchemaMethod},
+		{"Job.Callback", ErrUnsupportedType},
+		{"Job.Lookup[value]", ErrUnsupportedType},
+		{"Job.Embedded.Channel", ErrUnsupportedType},
+	}, problems)
+
+	var single *ConversionError
+	assert.True(t, errors.As(err, &single))
+	assert.Equal(t, "Job.State", single.Path)
+}
+
+func TestConvertERecursive(t *testing.T) {
+	type Node struct {
+		Children []Node
+		Visit    func()
+	}
+
+	c := NewConverter(map[string]CustomFn{})
+	_, err := c.ConvertE(Node{})
+
+	var errs ConversionErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "Node.Visit", errs[0].Path)
+}
+
+func TestConvertETopLevel(t *testing.T) {
+	c := NewConverter(map[string]CustomFn{})
+	_, err := c.ConvertE(42)
+
+	var errs ConversionErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "int", errs[0].Path)
+	assert.Equal(t, ErrUnsupportedType, errs[0].Code)
+}
+
+func TestConvertSliceE(t *testing.T) {
+	type Valid struct {
+		Name string
+	}
+	type Invalid struct {
+		Channel chan int
+	}
+
+	c := NewConverter(map[string]CustomFn{})
+	output, err := c.ConvertSliceE([]interface{}{Valid{}})
+	assert.NoError(t, err)
+	assert.Equal(t, `export const ValidSchema = z.object({
+  Name: z.string(),
+})
+export type Valid = z.infer<typeof ValidSchema>
+
+`, output)
+
+	c2 := NewConverter(map[string]CustomFn{})
+	_, err = c2.ConvertSliceE([]interface{}{Valid{}, Invalid{}})
+
+	var errs ConversionErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "Invalid.Channel", errs[0].Path)
+}
+
+func TestConvertEAfterFailure(t *testing.T) {
+	type Valid struct {
+		Name string
+	}
+	type Invalid struct {
+		Ch chan int
+	}
+
+	c := NewConverter(map[string]CustomFn{})
+	_, err := c.ConvertSliceE([]interface{}{Valid{}, Invalid{}})
+	require.Error(t, err)
+
+	// the schemas of the failed conversion are not kept.
+	output, err := c.ConvertE(Valid{})
+	assert.NoError(t, err)
+	assert.Equal(t, `export const ValidSchema = z.object({
+  Name: z.string(),
+})
+export type Valid = z.infer<typeof ValidSchema>
+
+`, output)
+}
+
+func TestConvertPanicsWithConversionError(t *testing.T) {
+	type User struct {
+		Channel chan int
+	}
+
+	defer func() {
+		err, ok := recover().(*ConversionError)
+		require.True(t, ok)
+		assert.Equal(t, "User.Channel", err.Path)
+	}()
+
+	StructToZodSchema(User{})
+}
@@ -0,0 +1,220 @@
+package supervillain
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// a field of a struct as encoding/json sees it, after embedded structs have
+// been promoted into their parent.
+type jsonField struct {
+	name   string
+	tagged bool
+	// the field indices from the top level struct, as in reflect.FieldByIndex.
+	index []int
+	// the Go field names from the top level struct, for errors.
+	path  []string
+	field reflect.StructField
+	typ   reflect.Type
+	// set for fields promoted through an embedded pointer, which are left out
+	// when the pointer is nil.
+	viaPointer bool
+}
+
+// lists the fields that encoding/json would marshal for a struct, in the order
+// it would marshal them. this follows the rules of encoding/json exactly:
+//
+//   - unexported fields are skipped, except embedded structs which may still
+//     have exported fields of their own
+//   - fields of embedded structs, or pointers to structs, are promoted unless
+//     the embedded field is given a name by its tag
+//   - when more than one field has the same name, the shallowest one wins, then
+//     the one with a tag, and if that still leaves more than one they are all
+//     dropped
+//
+// as an extension, any struct field tagged `json:",inline"` is promoted as if
+// it were embedded.
+func structFields(t reflect.Type) []jsonField {
+	current := []jsonField{}
+	next := []jsonField{{typ: t}}
+
+	// the number of times each type is embedded at the current and next depth,
+	// a type embedded more than once at the same depth conflicts with itself.
+	count := map[reflect.Type]int{}
+	nextCount := map[reflect.Type]int{}
+
+	visited := map[reflect.Type]bool{}
+
+	fields := []jsonField{}
+
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+
+		for _, f := range current {
+			if visited[f.typ] {
+				continue
+			}
+			visited[f.typ] = true
+
+			for i := 0; i < f.typ.NumField(); i++ {
+				sf := f.typ.Field(i)
+				if sf.Anonymous {
+					t := sf.Type
+					if t.Kind() == reflect.Ptr {
+						t = t.Elem()
+					}
+					if !sf.IsExported() && t.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts := parseTag(tag)
+				if !isValidTag(name) {
+					name = ""
+				}
+
+				index := make([]int, len(f.index)+1)
+				copy(index, f.index)
+				index[len(f.index)] = i
+
+				path := make([]string, len(f.path)+1)
+				copy(path, f.path)
+				path[len(f.path)] = sf.Name
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+
+				promote := (sf.Anonymous || opts.contains("inline")) &&
+					name == "" &&
+					ft.Kind() == reflect.Struct
+				if !promote {
+					tagged := name != ""
+					if name == "" {
+						name = sf.Name
+					}
+					fields = append(fields, jsonField{
+						name:       name,
+						tagged:     tagged,
+						index:      index,
+						path:       path,
+						field:      sf,
+						typ:        ft,
+						viaPointer: f.viaPointer,
+					})
+					if count[f.typ] > 1 {
+						// the parent was embedded more than once at this depth
+						// so add a duplicate to make sure the field is dropped.
+						fields = append(fields, fields[len(fields)-1])
+					}
+					continue
+				}
+
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					next = append(next, jsonField{
+						name:       ft.Name(),
+						index:      index,
+						path:       path,
+						typ:        ft,
+						viaPointer: f.viaPointer || sf.Type.Kind() == reflect.Ptr,
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		x := fields
+		if x[i].name != x[j].name {
+			return x[i].name < x[j].name
+		}
+		if len(x[i].index) != len(x[j].index) {
+			return len(x[i].index) < len(x[j].index)
+		}
+		if x[i].tagged != x[j].tagged {
+			return x[i].tagged
+		}
+		return byIndex(x[i].index, x[j].index)
+	})
+
+	// fields are now grouped by name and the dominant field is first in each.
+	out := fields[:0]
+	for advance, i := 0, 0; i < len(fields); i += advance {
+		fi := fields[i]
+		for advance = 1; i+advance < len(fields); advance++ {
+			if fields[i+advance].name != fi.name {
+				break
+			}
+		}
+		if advance == 1 {
+			out = append(out, fi)
+			continue
+		}
+		if len(fields[i].index) == len(fields[i+1].index) && fields[i].tagged == fields[i+1].tagged {
+			continue
+		}
+		out = append(out, fi)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return byIndex(out[i].index, out[j].index)
+	})
+
+	return out
+}
+
+func byIndex(a, b []int) bool {
+	for k, x := range a {
+		if k >= len(b) {
+			return false
+		}
+		if x != b[k] {
+			return x < b[k]
+		}
+	}
+	return len(a) < len(b)
+}
+
+type tagOptions string
+
+// splits a json struct tag into its name and options.
+func parseTag(tag string) (string, tagOptions) {
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, tagOptions(opts)
+}
+
+func (o tagOptions) contains(option string) bool {
+	for _, s := range strings.Split(string(o), ",") {
+		if s == option {
+			return true
+		}
+	}
+	return false
+}
+
+// checks whether a tag name is one that encoding/json accepts, otherwise it is
+// ignored and the field name is used instead.
+func isValidTag(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,298 @@
+package supervillain
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type embeddedBase struct {
+	ID   string
+	Name string `json:"name"`
+}
+
+type EmbeddedBase struct {
+	ID   string
+	Name string `json:"name"`
+}
+
+type EmbeddedOther struct {
+	ID    string
+	Other string
+}
+
+type EmbeddedTagged struct {
+	ID string `json:"ID"`
+}
+
+type EmbeddedDeep struct {
+	EmbeddedBase
+	Deep string
+}
+
+type EmbeddedPointer struct {
+	*EmbeddedDeep
+	Pointer string
+}
+
+type EmbeddedWrapperA struct {
+	EmbeddedBase
+}
+
+type EmbeddedWrapperB struct {
+	EmbeddedBase
+	B string
+}
+
+type EmbeddedName string
+
+type embeddedName string
+
+// the differential cases compare the fields found by structFields with the
+// keys that encoding/json actually writes for a fully populated value.
+var differentialCases = map[string]reflect.Type{
+	"simple": reflect.TypeOf(struct {
+		A string
+		B int `json:"b"`
+		C bool
+	}{}),
+	"omitted and renamed": reflect.TypeOf(struct {
+		A string `json:"-"`
+		B string `json:"-,"`
+		C string `json:",omitempty"`
+		D string `json:"d,omitempty"`
+	}{}),
+	"unexported fields": reflect.TypeOf(struct {
+		A string
+		b string
+		c int
+	}{}),
+	"embedded struct": reflect.TypeOf(struct {
+		EmbeddedBase
+		Other string
+	}{}),
+	"embedded pointer": reflect.TypeOf(struct {
+		*EmbeddedBase
+		Other string
+	}{}),
+	"embedded pointer in embedded struct": reflect.TypeOf(struct {
+		EmbeddedPointer
+		Other string
+	}{}),
+	"embedded unexported struct": reflect.TypeOf(struct {
+		embeddedBase
+		Other string
+	}{}),
+	"embedded with name": reflect.TypeOf(struct {
+		EmbeddedBase `json:"base"`
+	}{}),
+	"embedded ignored": reflect.TypeOf(struct {
+		EmbeddedBase `json:"-"`
+		Other        string
+	}{}),
+	"embedded non-struct": reflect.TypeOf(struct {
+		EmbeddedName
+		embeddedName
+	}{}),
+	"same depth conflict": reflect.TypeOf(struct {
+		EmbeddedBase
+		EmbeddedOther
+	}{}),
+	"same depth tagged wins": reflect.TypeOf(struct {
+		EmbeddedTagged
+		EmbeddedOther
+	}{}),
+	"shallower wins": reflect.TypeOf(struct {
+		EmbeddedDeep
+		EmbeddedOther
+		ID int
+	}{}),
+	"deeper loses to shallower untagged": reflect.TypeOf(struct {
+		EmbeddedDeep
+		Name string
+	}{}),
+	// these conflict in ways that go vet reports, so they are built at runtime.
+	"embedded twice at the same depth": embedding(
+		reflect.TypeOf(EmbeddedWrapperA{}),
+		reflect.TypeOf(EmbeddedWrapperB{}),
+	),
+	"embedded at different depths": embedding(
+		reflect.TypeOf(EmbeddedWrapperA{}),
+		reflect.TypeOf(EmbeddedDeep{}),
+	),
+}
+
+func embedding(types ...reflect.Type) reflect.Type {
+	fields := []reflect.StructField{}
+	for _, t := range types {
+		fields = append(fields, reflect.StructField{Name: t.Name(), Type: t, Anonymous: true})
+	}
+	return reflect.StructOf(fields)
+}
+
+func TestStructFieldsDifferential(t *testing.T) {
+	for name, typ := range differentialCases {
+		t.Run(name, func(t *testing.T) {
+			v := reflect.New(typ).Elem()
+			populate(v)
+
+			b, err := json.Marshal(v.Interface())
+			require.NoError(t, err)
+
+			keys := map[string]json.RawMessage{}
+			require.NoError(t, json.Unmarshal(b, &keys))
+
+			expected := []string{}
+			for k := range keys {
+				expected = append(expected, k)
+			}
+			sort.Strings(expected)
+
+			actual := []string{}
+			for _, f := range structFields(typ) {
+				actual = append(actual, f.name)
+			}
+			sort.Strings(actual)
+
+			assert.Equal(t, expected, actual, string(b))
+		})
+	}
+}
+
+func TestStructFieldsDifferentialNilEmbedded(t *testing.T) {
+	for name, typ := range differentialCases {
+		t.Run(name, func(t *testing.T) {
+			v := reflect.New(typ).Elem()
+			populate(v)
+			clearEmbeddedPointers(v)
+
+			b, err := json.Marshal(v.Interface())
+			require.NoError(t, err)
+
+			keys := map[string]json.RawMessage{}
+			require.NoError(t, json.Unmarshal(b, &keys))
+
+			// only the fields of nil embedded pointers are missing.
+			for _, f := range structFields(typ) {
+				_, ok := keys[f.name]
+				assert.Equal(t, !ok, f.viaPointer, "%s in %s", f.name, string(b))
+			}
+		})
+	}
+}
+
+// sets every embedded pointer back to nil.
+func clearEmbeddedPointers(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if !sf.Anonymous {
+			continue
+		}
+		switch sf.Type.Kind() {
+		case reflect.Ptr:
+			if v.Field(i).CanSet() {
+				v.Field(i).Set(reflect.Zero(sf.Type))
+			}
+		case reflect.Struct:
+			clearEmbeddedPointers(v.Field(i))
+		}
+	}
+}
+
+// fills in every field that can be set so that omitempty does not hide it.
+func populate(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			if !v.CanSet() {
+				return
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		populate(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			populate(v.Field(i))
+		}
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString("x")
+		}
+	case reflect.Int:
+		if v.CanSet() {
+			v.SetInt(1)
+		}
+	case reflect.Bool:
+		if v.CanSet() {
+			v.SetBool(true)
+		}
+	}
+}
+
+func TestStructFieldsOrder(t *testing.T) {
+	type Inner struct {
+		B string
+		C string
+	}
+	type Outer struct {
+		A string
+		Inner
+		D string
+	}
+
+	names := []string{}
+	for _, f := range structFields(reflect.TypeOf(Outer{})) {
+		names = append(names, f.name)
+	}
+	assert.Equal(t, []string{"A", "B", "C", "D"}, names)
+}
+
+func TestStructFieldsPath(t *testing.T) {
+	type Outer struct {
+		EmbeddedDeep
+	}
+
+	fields := structFields(reflect.TypeOf(Outer{}))
+	require.Len(t, fields, 3)
+	assert.Equal(t, []string{"EmbeddedDeep", "EmbeddedBase", "ID"}, fields[0].path)
+	assert.Equal(t, []int{0, 0, 0}, fields[0].index)
+}
+
+func TestEmbeddedStructsPromoted(t *testing.T) {
+	type Timestamps struct {
+		CreatedAt string `json:"createdAt"`
+		UpdatedAt string `json:"updatedAt"`
+	}
+	type Post struct {
+		ID string `json:"id"`
+		Timestamps
+	}
+	type User struct {
+		*Timestamps
+		ID    string `json:"id"`
+		Posts []Post `json:"posts"`
+		Name  string `json:"-"`
+	}
+
+	assert.Equal(t,
+		`export const PostSchema = z.object({
+  id: z.string(),
+  createdAt: z.string(),
+  updatedAt: z.string(),
+})
+export type Post = z.infer<typeof PostSchema>
+
+export const UserSchema = z.object({
+  createdAt: z.string().optional(),
+  updatedAt: z.string().optional(),
+  id: z.string(),
+  posts: PostSchema.array().nullable(),
+})
+export type User = z.infer<typeof UserSchema>
+
+`, StructToZodSchema(User{}))
+}
@@ -0,0 +1,383 @@
+package supervillain
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// GenericNaming configures how instantiations of generic structs are named when
+// they are emitted as concrete schemas with WithGenericInstances.
+type GenericNaming struct {
+	// Separator joins the name of the generic struct to its type arguments,
+	// defaults to "Of" as in `ResponseOfUser`.
+	Separator string
+	// ArgSeparator joins multiple type arguments, defaults to "And" as in
+	// `PairOfStringAndUser`.
+	ArgSeparator string
+	// Join replaces the default naming entirely. It is given the name of the
+	// generic struct and a valid identifier for each type argument.
+	Join func(name string, args []string) string
+}
+
+type genericInstancesOption GenericNaming
+
+func (g genericInstancesOption) apply(c *Converter) {
+	naming := GenericNaming(g)
+	if naming.Separator == "" {
+		naming.Separator = "Of"
+	}
+	if naming.ArgSeparator == "" {
+		naming.ArgSeparator = "And"
+	}
+	c.genericNaming = &naming
+}
+
+// WithGenericInstances emits a separate concrete schema for every instantiation
+// of a generic struct, instead of a single schema factory. Instantiations are
+// given valid identifiers such as `ResponseOfUserSchema`, any that still
+// collide are handled by the name collision policy.
+func WithGenericInstances(naming GenericNaming) Option {
+	return genericInstancesOption(naming)
+}
+
+// a type parameter of a generic struct, identified by the type argument it
+// was instantiated with.
+type genericParam struct {
+	// the type argument as it appears in the name of the instantiation.
+	arg string
+	// the TypeScript type parameter, such as `T`.
+	typ string
+}
+
+// checks whether a type is the type argument of the generic struct currently
+// being converted, if it is the schema argument is used in its place.
+//
+// reflection does not expose type parameters, only the instantiated type, so
+// any use of the type argument is assumed to be a use of the parameter. that
+// only holds when the argument is used once, which convertGeneric checks
+// before building a factory. where it is used is recorded, so that
+// instantiations for which the assumption gives a different factory can be
+// found.
+func (c *Converter) genericParam(t reflect.Type) (Schema, bool) {
+	if len(c.params) == 0 {
+		return nil, false
+	}
+
+	arg := typeArgString(t)
+	for _, p := range c.params {
+		if p.arg == arg {
+			path := c.currentPath()[c.paramsAt:]
+			c.paramUses[path] = append(c.paramUses[path], p.typ)
+			return &Param{Name: p.typ}, true
+		}
+	}
+
+	return nil, false
+}
+
+// converts an instantiation of a generic struct to a call to the schema
+// factory for that struct, declaring the factory if it does not exist yet.
+//
+// instantiations that cannot be told apart from the factory, because a type
+// argument is used more than once or in different fields than the factory
+// uses its parameter, are declared as a concrete schema of their own instead.
+func (c *Converter) convertGeneric(t reflect.Type, indent int) Schema {
+	base, generic := getFullName(t)
+	args := splitTypeArgs(generic)
+
+	if c.isCycle(base) {
+		c.fail(t, ErrUnsupportedType, fmt.Sprint("generic types cannot refer to themselves: ", t.Name()))
+		return &Primitive{Kind: PrimitiveUnknown}
+	}
+
+	if !unambiguousTypeArgs(t, args) {
+		return c.convertNamedStruct(t)
+	}
+	if _, ok := c.outputs[base]; ok && !c.fitsFactory(t, base, args) {
+		return c.convertNamedStruct(t)
+	}
+
+	name := c.claimName(base, t.Name()[:strings.Index(t.Name(), "[")], t)
+	if _, ok := c.outputs[base]; !ok {
+		c.addSchema(c.convertGenericTopLevel(t, base, name, args))
+	}
+	c.addDependency(base)
+
+	values := []Schema{}
+	for i, argType := range findTypeArgs(t, args) {
+		if argType == nil {
+			c.fail(t, ErrUnsupportedType, fmt.Sprintf("type argument %s of %s is not used by any field", args[i], t.Name()))
+			values = append(values, &Primitive{Kind: PrimitiveUnknown})
+			continue
+		}
+		values = append(values, c.convertSchema(argType, typeName(argType), indent))
+	}
+
+	return &Reference{Key: base, Name: name, Args: values}
+}
+
+func (c *Converter) convertGenericTopLevel(t reflect.Type, key, name string, args []string) *Declaration {
+	schema, params, uses := c.convertGenericStruct(t, key, args)
+	if c.genericUses == nil {
+		c.genericUses = map[string]map[string][]string{}
+	}
+	c.genericUses[key] = uses
+
+	d := c.declare(t, key, name, schema, false)
+	for _, p := range params {
+		d.Params = append(d.Params, p.typ)
+	}
+	return d
+}
+
+// converts the fields of a generic struct with its type arguments replaced by
+// type parameters, along with where each parameter is used.
+func (c *Converter) convertGenericStruct(t reflect.Type, key string, args []string) (Schema, []genericParam, map[string][]string) {
+	params := make([]genericParam, len(args))
+	for i, arg := range args {
+		if len(args) == 1 {
+			params[i] = genericParam{arg, "T"}
+		} else {
+			params[i] = genericParam{arg, fmt.Sprintf("T%d", i+1)}
+		}
+	}
+
+	saved, savedUses, savedAt := c.params, c.paramUses, c.paramsAt
+	c.params, c.paramUses, c.paramsAt = params, map[string][]string{}, len(c.currentPath())
+	c.push(key)
+	schema := c.convertStruct(t, 0)
+	c.pop()
+	uses := c.paramUses
+	c.params, c.paramUses, c.paramsAt = saved, savedUses, savedAt
+
+	return schema, params, uses
+}
+
+// checks that an instantiation uses its type arguments in the same fields as
+// the one the factory was built from, so that the factory is right for it too.
+func (c *Converter) fitsFactory(t reflect.Type, key string, args []string) bool {
+	if fits, ok := c.genericFits[t]; ok {
+		return fits
+	}
+
+	_, _, uses := c.convertGenericStruct(t, key, args)
+	fits := reflect.DeepEqual(uses, c.genericUses[key])
+	if c.genericFits == nil {
+		c.genericFits = map[reflect.Type]bool{}
+	}
+	c.genericFits[t] = fits
+	return fits
+}
+
+// checks that each type argument is reached exactly once by the fields of a
+// generic struct, as genericParam would reach it. a type argument that is
+// reached more than once, such as string in `Page[string]` with a field
+// `Next string`, may be a concrete type rather than the parameter, so no
+// factory can be built from the instantiation. counting too many is safe,
+// since the instantiation is then declared as a concrete schema.
+func unambiguousTypeArgs(t reflect.Type, args []string) bool {
+	counts := make([]int, len(args))
+
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		matched := false
+		arg := typeArgString(t)
+		for i := range args {
+			if args[i] == arg {
+				counts[i]++
+				matched = true
+			}
+		}
+		if matched {
+			return
+		}
+
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			walk(t.Elem())
+		case reflect.Map:
+			walk(t.Key())
+			walk(t.Elem())
+		case reflect.Struct:
+			switch {
+			case t.Name() == "":
+				for _, f := range structFields(t) {
+					walk(f.typ)
+				}
+			case isGeneric(t):
+				_, generic := getFullName(t)
+				for _, argType := range findTypeArgs(t, splitTypeArgs(generic)) {
+					if argType != nil {
+						walk(argType)
+					}
+				}
+			}
+			// other named structs are declared on their own, without the type
+			// parameters.
+		}
+	}
+	for _, f := range structFields(t) {
+		walk(f.typ)
+	}
+
+	for _, n := range counts {
+		if n > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// splits the type arguments of a generic type name, ignoring any commas that
+// belong to nested type arguments.
+func splitTypeArgs(generic string) []string {
+	args := []string{}
+	depth := 0
+	start := 0
+	for i, r := range generic {
+		switch r {
+		case '[', '{', '(':
+			depth++
+		case ']', '}', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, generic[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(args, generic[start:])
+}
+
+// formats a type the same way it appears as a type argument in the name of a
+// generic type, for example `github.com/acme/users.User` or `[]string`.
+func typeArgString(t reflect.Type) string {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name()
+		}
+		return typeKey(t)
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + typeArgString(t.Elem())
+	case reflect.Slice:
+		return "[]" + typeArgString(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), typeArgString(t.Elem()))
+	case reflect.Map:
+		return fmt.Sprintf("map[%s]%s", typeArgString(t.Key()), typeArgString(t.Elem()))
+	}
+
+	return t.String()
+}
+
+// finds the type of each type argument by searching the fields of a generic
+// struct. arguments that are not used by any field are left nil.
+func findTypeArgs(t reflect.Type, args []string) []reflect.Type {
+	found := make([]reflect.Type, len(args))
+	seen := map[reflect.Type]bool{}
+
+	var search func(t reflect.Type)
+	search = func(t reflect.Type) {
+		if seen[t] {
+			return
+		}
+		seen[t] = true
+
+		arg := typeArgString(t)
+		for i := range args {
+			if found[i] == nil && args[i] == arg {
+				found[i] = t
+			}
+		}
+
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			search(t.Elem())
+		case reflect.Map:
+			search(t.Key())
+			search(t.Elem())
+		case reflect.Struct:
+			for i := 0; i < t.NumField(); i++ {
+				search(t.Field(i).Type)
+			}
+		}
+	}
+	search(t)
+
+	return found
+}
+
+// builds an identifier for an instantiation of a generic struct from the names
+// of its type arguments.
+func (c *Converter) instanceName(t reflect.Type) string {
+	_, generic := getFullName(t)
+	args := splitTypeArgs(generic)
+	name := t.Name()[:strings.Index(t.Name(), "[")]
+	naming := c.instanceNaming()
+
+	idents := make([]string, len(args))
+	for i, argType := range findTypeArgs(t, args) {
+		if argType == nil {
+			idents[i] = sanitiseIdentifier(args[i])
+		} else {
+			idents[i] = c.identifierFor(argType)
+		}
+	}
+
+	if naming.Join != nil {
+		return naming.Join(name, idents)
+	}
+
+	return name + naming.Separator + strings.Join(idents, naming.ArgSeparator)
+}
+
+// gets how instantiations are named, which is the default naming for those
+// declared concretely without WithGenericInstances.
+func (c *Converter) instanceNaming() GenericNaming {
+	if c.genericNaming != nil {
+		return *c.genericNaming
+	}
+	return GenericNaming{Separator: "Of", ArgSeparator: "And"}
+}
+
+// gets a readable identifier for a type argument, without package paths.
+func (c *Converter) identifierFor(t reflect.Type) string {
+	if isGeneric(t) {
+		return c.instanceName(t)
+	}
+	if t.Name() != "" {
+		return sanitiseIdentifier(t.Name())
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return c.identifierFor(t.Elem())
+	case reflect.Slice, reflect.Array:
+		return c.identifierFor(t.Elem()) + "Array"
+	case reflect.Map:
+		return c.identifierFor(t.Key()) + "To" + c.identifierFor(t.Elem()) + "Map"
+	case reflect.Interface:
+		return "Any"
+	case reflect.Struct:
+		return "Object"
+	}
+
+	return sanitiseIdentifier(t.Kind().String())
+}
+
+// turns an arbitrary type string into an identifier by dropping package paths.
+func sanitiseIdentifier(s string) string {
+	if i := strings.LastIndex(s, "/"); i >= 0 {
+		s = s[i+1:]
+	}
+	if i := strings.LastIndex(s, "."); i >= 0 {
+		s = s[i+1:]
+	}
+
+	return pascalCase(s)
+}
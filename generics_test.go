@@ -0,0 +1,375 @@
+package supervillain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Southclaws/supervillain/internal/fixtures/auth"
+	"github.com/Southclaws/supervillain/internal/fixtures/billing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Page[T any] struct {
+	Items []T
+	Next  string
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value *V
+}
+
+type Member struct {
+	Name string
+}
+
+type Directory struct {
+	Members Page[Member]
+	Counts  Page[int]
+	Lookup  Pair[int, Member]
+	Nested  Page[Page[Member]]
+}
+
+func TestGenericFactory(t *testing.T) {
+	assert.Equal(t,
+		`export const MemberSchema = z.object({
+  Name: z.string(),
+})
+export type Member = z.infer<typeof MemberSchema>
+
+export const PageSchema = <T extends z.ZodTypeAny>(t: T) => z.object({
+  Items: t.array().nullable(),
+  Next: z.string(),
+})
+export type Page<T extends z.ZodTypeAny> = z.infer<ReturnType<typeof PageSchema<T>>>
+
+export const PairSchema = <T1 extends z.ZodTypeAny, T2 extends z.ZodTypeAny>(t1: T1, t2: T2) => z.object({
+  Key: t1,
+  Value: t2.nullable(),
+})
+export type Pair<T1 extends z.ZodTypeAny, T2 extends z.ZodTypeAny> = z.infer<ReturnType<typeof PairSchema<T1, T2>>>
+
+export const DirectorySchema = z.object({
+  Members: PageSchema(MemberSchema),
+  Counts: PageSchema(z.number().int()),
+  Lookup: PairSchema(z.number().int(), MemberSchema),
+  Nested: PageSchema(PageSchema(MemberSchema)),
+})
+export type Directory = z.infer<typeof DirectorySchema>
+
+`, StructToZodSchema(Directory{}))
+}
+
+type Listing struct {
+	Names   Page[string]
+	Members Page[Member]
+}
+
+type ReversedListing struct {
+	Members Page[Member]
+	Names   Page[string]
+}
+
+func TestGenericFactoryAmbiguousArgs(t *testing.T) {
+	// Next has the same type as the type argument of Page[string], so it
+	// cannot be told apart from a use of T and Page[string] is declared on
+	// its own, whichever instantiation is found first.
+	for _, tc := range []struct {
+		input  interface{}
+		fields string
+	}{
+		{Listing{}, `export const ListingSchema = z.object({
+  Names: PageOfStringSchema,
+  Members: PageSchema(MemberSchema),
+})
+export type Listing = z.infer<typeof ListingSchema>
+
+`},
+		{ReversedListing{}, `export const ReversedListingSchema = z.object({
+  Members: PageSchema(MemberSchema),
+  Names: PageOfStringSchema,
+})
+export type ReversedListing = z.infer<typeof ReversedListingSchema>
+
+`},
+	} {
+		c := NewConverter(map[string]CustomFn{})
+		out, err := c.ConvertE(tc.input)
+		require.NoError(t, err)
+		assert.Equal(t, `export const MemberSchema = z.object({
+  Name: z.string(),
+})
+export type Member = z.infer<typeof MemberSchema>
+
+export const PageSchema = <T extends z.ZodTypeAny>(t: T) => z.object({
+  Items: t.array().nullable(),
+  Next: z.string(),
+})
+export type Page<T extends z.ZodTypeAny> = z.infer<ReturnType<typeof PageSchema<T>>>
+
+export const PageOfStringSchema = z.object({
+  Items: z.string().array().nullable(),
+  Next: z.string(),
+})
+export type PageOfString = z.infer<typeof PageOfStringSchema>
+
+`+tc.fields, out)
+	}
+
+	c := NewConverter(map[string]CustomFn{})
+	out, err := c.ConvertSliceE([]interface{}{Page[string]{}, Page[Member]{}})
+	require.NoError(t, err)
+	assert.Equal(t, `export const MemberSchema = z.object({
+  Name: z.string(),
+})
+export type Member = z.infer<typeof MemberSchema>
+
+export const PageSchema = <T extends z.ZodTypeAny>(t: T) => z.object({
+  Items: t.array().nullable(),
+  Next: z.string(),
+})
+export type Page<T extends z.ZodTypeAny> = z.infer<ReturnType<typeof PageSchema<T>>>
+
+export const PageOfStringSchema = z.object({
+  Items: z.string().array().nullable(),
+  Next: z.string(),
+})
+export type PageOfString = z.infer<typeof PageOfStringSchema>
+
+`, out)
+}
+
+func TestGenericFactoryAmbiguousArgsAlone(t *testing.T) {
+	type Catalogue struct {
+		Tags Page[string]
+	}
+
+	c := NewConverter(map[string]CustomFn{})
+	out, err := c.ConvertE(Catalogue{})
+	require.NoError(t, err)
+	assert.Equal(t, `export const PageOfStringSchema = z.object({
+  Items: z.string().array().nullable(),
+  Next: z.string(),
+})
+export type PageOfString = z.infer<typeof PageOfStringSchema>
+
+export const CatalogueSchema = z.object({
+  Tags: PageOfStringSchema,
+})
+export type Catalogue = z.infer<typeof CatalogueSchema>
+
+`, out)
+}
+
+func TestGenericFactoryTopLevel(t *testing.T) {
+	c := NewConverter(map[string]CustomFn{})
+	assert.Equal(t,
+		`export const MemberSchema = z.object({
+  Name: z.string(),
+})
+export type Member = z.infer<typeof MemberSchema>
+
+export const PageSchema = <T extends z.ZodTypeAny>(t: T) => z.object({
+  Items: t.array().nullable(),
+  Next: z.string(),
+})
+export type Page<T extends z.ZodTypeAny> = z.infer<ReturnType<typeof PageSchema<T>>>
+
+`, c.ConvertSlice([]interface{}{Page[Member]{}, Page[int]{}}))
+}
+
+type Thread struct {
+	Title   string
+	Replies Page[Thread]
+}
+
+func TestGenericFactoryRecursive(t *testing.T) {
+	assert.Equal(t,
+		`export const PageSchema = <T extends z.ZodTypeAny>(t: T) => z.object({
+  Items: t.array().nullable(),
+  Next: z.string(),
+})
+export type Page<T extends z.ZodTypeAny> = z.infer<ReturnType<typeof PageSchema<T>>>
+
+export type Thread = {
+  Title: string
+  Replies: Page<z.ZodType<Thread>>
+}
+export const ThreadSchema: z.ZodType<Thread> = z.object({
+  Title: z.string(),
+  Replies: PageSchema(z.lazy(() => ThreadSchema)),
+})
+
+`, StructToZodSchema(Thread{}))
+}
+
+type Tree[T any] struct {
+	Value    T
+	Children []Tree[T]
+}
+
+// the nested Tree[string] uses the type argument again, so the instantiation
+// is declared on its own and refers to itself like any other struct.
+func TestGenericSelfReference(t *testing.T) {
+	type Forest struct {
+		Trees []Tree[string]
+	}
+
+	c := NewConverter(map[string]CustomFn{})
+	out, err := c.ConvertE(Forest{})
+	require.NoError(t, err)
+	assert.Equal(t, `export type TreeOfString = {
+  Value: string
+  Children: TreeOfString[] | null
+}
+export const TreeOfStringSchema: z.ZodType<TreeOfString> = z.object({
+  Value: z.string(),
+  Children: z.lazy(() => TreeOfStringSchema).array().nullable(),
+})
+
+export const ForestSchema = z.object({
+  Trees: TreeOfStringSchema.array().nullable(),
+})
+export type Forest = z.infer<typeof ForestSchema>
+
+`, out)
+}
+
+func TestSplitTypeArgs(t *testing.T) {
+	assert.Equal(t, []string{"string"}, splitTypeArgs("string"))
+	assert.Equal(t, []string{"string", "main.Page[int]"}, splitTypeArgs("string,main.Page[int]"))
+	assert.Equal(t, []string{"map[string]int", "struct { A int; B int }"}, splitTypeArgs("map[string]int,struct { A int; B int }"))
+}
+
+func TestTypeArgString(t *testing.T) {
+	for _, v := range []interface{}{
+		Page[Member]{},
+		Page[*Member]{},
+		Page[[]Member]{},
+		Page[[3]int]{},
+		Page[map[string]Member]{},
+		Page[Page[Member]]{},
+		Page[interface{}]{},
+	} {
+		typ := reflect.TypeOf(v)
+		_, generic := getFullName(typ)
+		assert.Equal(t, generic, typeArgString(typ.Field(0).Type.Elem()))
+	}
+}
+
+func TestGenericInstances(t *testing.T) {
+	assert.Equal(t,
+		`export const MemberSchema = z.object({
+  Name: z.string(),
+})
+export type Member = z.infer<typeof MemberSchema>
+
+export const PageOfIntSchema = z.object({
+  Items: z.number().int().array().nullable(),
+  Next: z.string(),
+})
+export type PageOfInt = z.infer<typeof PageOfIntSchema>
+
+export const PageOfMemberSchema = z.object({
+  Items: MemberSchema.array().nullable(),
+  Next: z.string(),
+})
+export type PageOfMember = z.infer<typeof PageOfMemberSchema>
+
+export const PageOfPageOfMemberSchema = z.object({
+  Items: PageOfMemberSchema.array().nullable(),
+  Next: z.string(),
+})
+export type PageOfPageOfMember = z.infer<typeof PageOfPageOfMemberSchema>
+
+export const PairOfIntAndMemberSchema = z.object({
+  Key: z.number().int(),
+  Value: MemberSchema.nullable(),
+})
+export type PairOfIntAndMember = z.infer<typeof PairOfIntAndMemberSchema>
+
+export const DirectorySchema = z.object({
+  Members: PageOfMemberSchema,
+  Counts: PageOfIntSchema,
+  Lookup: PairOfIntAndMemberSchema,
+  Nested: PageOfPageOfMemberSchema,
+})
+export type Directory = z.infer<typeof DirectorySchema>
+
+`, StructToZodSchema(Directory{}, WithGenericInstances(GenericNaming{})))
+}
+
+func TestGenericInstancesRecursive(t *testing.T) {
+	type Forest struct {
+		Trees []Tree[string]
+	}
+
+	assert.Equal(t,
+		`export type TreeOfString = {
+  Value: string
+  Children: TreeOfString[] | null
+}
+export const TreeOfStringSchema: z.ZodType<TreeOfString> = z.object({
+  Value: z.string(),
+  Children: z.lazy(() => TreeOfStringSchema).array().nullable(),
+})
+
+export const ForestSchema = z.object({
+  Trees: TreeOfStringSchema.array().nullable(),
+})
+export type Forest = z.infer<typeof ForestSchema>
+
+`, StructToZodSchema(Forest{}, WithGenericInstances(GenericNaming{})))
+}
+
+func TestGenericInstancesNaming(t *testing.T) {
+	type Lists struct {
+		Pairs Pair[string, []map[string]Member]
+	}
+
+	assert.Equal(t,
+		`export const MemberSchema = z.object({
+  Name: z.string(),
+})
+export type Member = z.infer<typeof MemberSchema>
+
+export const Pair_String_StringToMemberMapArraySchema = z.object({
+  Key: z.string(),
+  Value: z.record(z.string(), MemberSchema).array().nullable(),
+})
+export type Pair_String_StringToMemberMapArray = z.infer<typeof Pair_String_StringToMemberMapArraySchema>
+
+export const ListsSchema = z.object({
+  Pairs: Pair_String_StringToMemberMapArraySchema,
+})
+export type Lists = z.infer<typeof ListsSchema>
+
+`, StructToZodSchema(Lists{}, WithGenericInstances(GenericNaming{
+			Separator:    "_",
+			ArgSeparator: "_",
+		})))
+
+	c := NewConverter(map[string]CustomFn{}, WithGenericInstances(GenericNaming{
+		Join: func(name string, args []string) string {
+			return args[0] + name
+		},
+	}))
+	assert.Contains(t, c.Convert(Directory{}), "export const MemberPageSchema = z.object({")
+}
+
+func TestGenericInstancesCollision(t *testing.T) {
+	type Accounts struct {
+		Billing Page[billing.Account]
+		Auth    Page[auth.Account]
+	}
+
+	c := NewConverter(map[string]CustomFn{}, WithGenericInstances(GenericNaming{}))
+	_, err := c.ConvertE(Accounts{})
+
+	var errs ConversionErrors
+	require.True(t, errors.As(err, &errs))
+	assert.Equal(t, "Accounts.Auth", errs[0].Path)
+	assert.Equal(t, ErrNameCollision, errs[0].Code)
+}
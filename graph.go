@@ -0,0 +1,69 @@
+package supervillain
+
+import "sort"
+
+// sorts schemas topologically so that every schema comes after the schemas it
+// depends on. when more than one schema is ready to be written, the one with
+// the lowest name goes first so the output does not depend on the order in
+// which types were discovered.
+func sortSchemas(outputs map[string]entry) []string {
+	less := func(a, b string) bool {
+		if outputs[a].name != outputs[b].name {
+			return outputs[a].name < outputs[b].name
+		}
+		return a < b
+	}
+
+	// the number of dependencies for each schema that are yet to be written.
+	pending := make(map[string]int, len(outputs))
+	// the reverse of each edge, from a schema to the schemas that depend on it.
+	dependents := make(map[string][]string, len(outputs))
+
+	for name, ent := range outputs {
+		pending[name] += 0
+		for _, dep := range ent.deps {
+			if _, ok := outputs[dep]; !ok {
+				continue
+			}
+			pending[name]++
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	ready := []string{}
+	for name, n := range pending {
+		if n == 0 {
+			ready = append(ready, name)
+		}
+	}
+	sort.Slice(ready, func(i, j int) bool { return less(ready[i], ready[j]) })
+
+	sorted := make([]string, 0, len(outputs))
+	for len(ready) > 0 {
+		name := ready[0]
+		ready = ready[1:]
+		sorted = append(sorted, name)
+		delete(pending, name)
+
+		for _, dependent := range dependents[name] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				i := sort.Search(len(ready), func(i int) bool { return !less(ready[i], dependent) })
+				ready = append(ready, "")
+				copy(ready[i+1:], ready[i:])
+				ready[i] = dependent
+			}
+		}
+	}
+
+	// eager references never form a cycle because the reference that closes a
+	// cycle is always lazy, but if one did slip through the remaining schemas
+	// are still written out rather than silently dropped.
+	remaining := []string{}
+	for name := range pending {
+		remaining = append(remaining, name)
+	}
+	sort.Slice(remaining, func(i, j int) bool { return less(remaining[i], remaining[j]) })
+
+	return append(sorted, remaining...)
+}
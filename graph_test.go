@@ -0,0 +1,67 @@
+package supervillain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortSchemasByName(t *testing.T) {
+	assert.Equal(t,
+		[]string{"A", "B", "C"},
+		sortSchemas(map[string]entry{
+			"C": {},
+			"A": {},
+			"B": {},
+		}))
+}
+
+func TestSortSchemasDependencies(t *testing.T) {
+	assert.Equal(t,
+		[]string{"Post", "Comment", "Author", "User"},
+		sortSchemas(map[string]entry{
+			"User":    {deps: []string{"Post", "Author"}},
+			"Author":  {deps: []string{"Comment"}},
+			"Comment": {deps: []string{"Post"}},
+			"Post":    {},
+		}))
+}
+
+func TestSortSchemasDiamond(t *testing.T) {
+	assert.Equal(t,
+		[]string{"D", "B", "C", "A"},
+		sortSchemas(map[string]entry{
+			"A": {deps: []string{"C", "B"}},
+			"B": {deps: []string{"D"}},
+			"C": {deps: []string{"D"}},
+			"D": {},
+		}))
+}
+
+func TestSortSchemasUnknownDependency(t *testing.T) {
+	assert.Equal(t,
+		[]string{"A", "B"},
+		sortSchemas(map[string]entry{
+			"A": {deps: []string{"Missing"}},
+			"B": {deps: []string{"A"}},
+		}))
+}
+
+func TestSortSchemasCycle(t *testing.T) {
+	assert.Equal(t,
+		[]string{"C", "A", "B"},
+		sortSchemas(map[string]entry{
+			"A": {deps: []string{"B"}},
+			"B": {deps: []string{"A"}},
+			"C": {},
+		}))
+}
+
+func TestSortSchemasTieBreakByName(t *testing.T) {
+	assert.Equal(t,
+		[]string{"z.Account", "a.User"},
+		sortSchemas(map[string]entry{
+			"a.User":    {name: "User"},
+			"z.Account": {name: "Account"},
+		}))
+}
@@ -0,0 +1,7 @@
+// Package auth contains types used to test conversion of types with the same
+// name from different packages.
+package auth
+
+type Account struct {
+	Email string
+}
@@ -0,0 +1,7 @@
+// Package billing contains types used to test conversion of types with the
+// same name from different packages.
+package billing
+
+type Account struct {
+	Balance int
+}
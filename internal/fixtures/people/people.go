@@ -0,0 +1,6 @@
+// Package people contains types used to test splitting schemas into modules.
+package people
+
+type Person struct {
+	Name string
+}
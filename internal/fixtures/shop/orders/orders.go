@@ -0,0 +1,14 @@
+// Package orders contains types used to test splitting schemas into modules.
+package orders
+
+import "github.com/Southclaws/supervillain/internal/fixtures/people"
+
+type Order struct {
+	Buyer  people.Person
+	Lines  []Line
+	Parent *Order
+}
+
+type Line struct {
+	Product string
+}
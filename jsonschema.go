@@ -0,0 +1,490 @@
+package supervillain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidJSONSchema is reported for custom types whose JSON Schema is not
+// valid JSON.
+const ErrInvalidJSONSchema ErrorCode = "invalid_json_schema"
+
+// the draft of JSON Schema that is written out.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// ConstantJSONSchema is implemented by custom types that describe themselves in
+// JSON Schema, alongside their ZodSchema method. Without it, custom types
+// accept any value in JSON Schema.
+type ConstantJSONSchema interface {
+	JSONSchema() string
+}
+
+type customJSONSchemasOption map[string]string
+
+func (j customJSONSchemasOption) apply(c *Converter) {
+	c.customJSONSchemas = j
+}
+
+// WithCustomJSONSchemas gives the JSON Schema of custom types, keyed by their
+// full name as in the map of CustomFn given to NewConverter, such as
+// `github.com/shopspring/decimal.Decimal`.
+func WithCustomJSONSchemas(schemas map[string]string) Option {
+	return customJSONSchemasOption(schemas)
+}
+
+// gets the JSON Schema a custom type supplies, if any.
+func (c *Converter) customJSONSchema(t reflect.Type) string {
+	schema := customSchema(t, c.customJSONSchemas, ConstantJSONSchema.JSONSchema)
+	if schema != "" && !json.Valid([]byte(schema)) {
+		fullName, _ := getFullName(t)
+		c.fail(t, ErrInvalidJSONSchema, fmt.Sprintf("JSON Schema of %s is not valid JSON: %s", fullName, schema))
+		return ""
+	}
+	return schema
+}
+
+// ConvertJSONSchema converts the inputs like ConvertSlice, but to a JSON Schema
+// document with a definition in `$defs` for every named type. When there is one
+// input, the document refers to its definition so that it validates values of
+// that type.
+func (c *Converter) ConvertJSONSchema(inputs []interface{}) string {
+	decls := c.Declarations(inputs)
+
+	var root *Declaration
+	if len(inputs) == 1 {
+		root = c.outputs[typeKey(reflect.TypeOf(inputs[0]))].decl
+	}
+
+	output, err := JSONSchemaPrinter{Prefix: c.prefix}.Document(decls, root)
+	if err != nil {
+		c.fail(nil, ErrInvalidJSONSchema, err.Error())
+	}
+	return output
+}
+
+// ConvertJSONSchemaE is like ConvertJSONSchema but returns every problem found
+// in the types as a ConversionErrors instead of panicking on the first one.
+func (c *Converter) ConvertJSONSchemaE(inputs []interface{}) (string, error) {
+	return c.collectErrors(func() string {
+		return c.ConvertJSONSchema(inputs)
+	})
+}
+
+// JSONSchemaPrinter prints declarations as a JSON Schema (draft 2020-12)
+// document. It describes the JSON that encoding/json writes, so fields with the
+// `,string` option are strings even when they are coerced by Zod.
+//
+// JSON Schema has no type parameters, so generic declarations are not defined
+// in `$defs`. Each instantiation is written out in full where it is used.
+type JSONSchemaPrinter struct {
+	// Prefix is added to the start of every definition name.
+	Prefix string
+}
+
+// Document prints a JSON Schema document that defines every declaration in
+// `$defs`. When root is given, the document refers to its definition.
+func (p JSONSchemaPrinter) Document(decls []*Declaration, root *Declaration) (string, error) {
+	b := jsonSchemaBuilder{prefix: p.Prefix, decls: map[string]*Declaration{}}
+	for _, d := range decls {
+		b.decls[d.Key] = d
+	}
+
+	defs := &jsonObject{}
+	for _, d := range decls {
+		if len(d.Params) > 0 {
+			continue
+		}
+		defs.set(p.Prefix+d.Name, b.declaration(d, nil))
+	}
+
+	doc := &jsonObject{}
+	doc.set("$schema", jsonSchemaDialect)
+	if root != nil && len(root.Params) == 0 {
+		doc.set("$ref", b.ref(root.Name))
+	}
+	doc.set("$defs", defs)
+
+	output, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(output) + "\n", nil
+}
+
+type jsonSchemaBuilder struct {
+	prefix string
+	// declarations by key, to write out instantiations of generic ones.
+	decls map[string]*Declaration
+}
+
+func (b jsonSchemaBuilder) ref(name string) string {
+	return fmt.Sprintf("#/$defs/%s%s", b.prefix, name)
+}
+
+// builds the schema of a declaration, with its type parameters bound to the
+// schemas of the type arguments it was instantiated with.
+func (b jsonSchemaBuilder) declaration(d *Declaration, params map[string]*jsonObject) *jsonObject {
+	schema := b.schema(d.Schema, params)
+	if d.Description != "" {
+		schema.set("description", d.Description)
+	}
+	if _, _, deprecated := parseDoc(d.Doc); deprecated {
+		schema.set("deprecated", true)
+	}
+	return schema
+}
+
+func (b jsonSchemaBuilder) schema(s Schema, params map[string]*jsonObject) *jsonObject {
+	schema := &jsonObject{}
+
+	switch s := s.(type) {
+	case *Primitive:
+		b.primitive(schema, s)
+
+	case *Const:
+		schema.set("const", json.RawMessage(s.Value))
+
+	case *Enum:
+		values := make([]json.RawMessage, len(s.Values))
+		for i, v := range s.Values {
+			values[i] = json.RawMessage(v)
+		}
+		schema.set("enum", values)
+
+	case *Object:
+		properties := &jsonObject{}
+		required := []string{}
+		for _, f := range s.Fields {
+			properties.set(f.Name, b.field(f, params))
+			if !f.Optional {
+				required = append(required, f.Name)
+			}
+		}
+		schema.set("type", "object")
+		schema.set("properties", properties)
+		if len(required) > 0 {
+			schema.set("required", required)
+		}
+
+	case *Array:
+		schema.set("type", "array")
+		schema.set("items", b.schema(s.Items, params))
+		for _, check := range s.Checks {
+			switch check.Kind {
+			case CheckMin:
+				schema.set("minItems", json.Number(check.Value))
+			case CheckMax:
+				schema.set("maxItems", json.Number(check.Value))
+			case CheckLength:
+				schema.set("minItems", json.Number(check.Value))
+				schema.set("maxItems", json.Number(check.Value))
+			}
+		}
+
+	case *Tuple:
+		items := make([]interface{}, len(s.Items))
+		for i, item := range s.Items {
+			items[i] = b.schema(item, params)
+		}
+		schema.set("type", "array")
+		schema.set("prefixItems", items)
+		schema.set("items", false)
+		schema.set("minItems", len(items))
+
+	case *Record:
+		schema.set("type", "object")
+		if isStringKey(s.Key) {
+			schema.set("propertyNames", b.schema(s.Key, params))
+		}
+		schema.set("additionalProperties", b.schema(s.Value, params))
+
+	case *Union:
+		if len(s.Members) == 1 {
+			return b.schema(s.Members[0], params)
+		}
+		members := make([]interface{}, len(s.Members))
+		for i, m := range s.Members {
+			members[i] = b.schema(m, params)
+		}
+		if s.Discriminator != "" {
+			schema.set("oneOf", members)
+		} else {
+			schema.set("anyOf", members)
+		}
+
+	case *OrEmpty:
+		empty := &jsonObject{}
+		empty.set("const", json.RawMessage(s.Value))
+		schema.set("anyOf", []interface{}{b.schema(s.Schema, params), empty})
+
+	case *Reference:
+		d, ok := b.decls[s.Key]
+		if !ok || len(d.Params) == 0 {
+			schema.set("$ref", b.ref(s.Name))
+			break
+		}
+		args := map[string]*jsonObject{}
+		for i, param := range d.Params {
+			if i < len(s.Args) {
+				args[param] = b.schema(s.Args[i], params)
+			}
+		}
+		return b.declaration(d, args)
+
+	case *Param:
+		if arg, ok := params[s.Name]; ok {
+			return arg.copy()
+		}
+
+	case *Custom:
+		if s.JSONSchema != "" {
+			return parseJSONObject(s.JSONSchema)
+		}
+	}
+
+	return schema
+}
+
+func (b jsonSchemaBuilder) field(f *Field, params map[string]*jsonObject) *jsonObject {
+	schema := b.schema(f.Schema, params)
+	if f.Nullable {
+		schema = nullable(schema)
+	}
+	if f.Default != "" && json.Valid([]byte(f.Default)) {
+		schema.set("default", json.RawMessage(f.Default))
+	}
+	if f.Description != "" {
+		schema.set("description", f.Description)
+	}
+	if _, _, deprecated := parseDoc(f.Doc); deprecated {
+		schema.set("deprecated", true)
+	}
+	return schema
+}
+
+// the keywords for the checks on a number, which take the value of the check.
+var jsonSchemaNumberChecks = map[CheckKind]string{
+	CheckMin:                "minimum",
+	CheckMax:                "maximum",
+	CheckGreaterThan:        "exclusiveMinimum",
+	CheckGreaterThanOrEqual: "minimum",
+	CheckLessThan:           "exclusiveMaximum",
+	CheckLessThanOrEqual:    "maximum",
+}
+
+// the formats for string checks that JSON Schema has a format for.
+var jsonSchemaFormats = map[CheckKind]string{
+	CheckEmail: "email",
+	CheckURL:   "uri",
+	CheckUUID:  "uuid",
+}
+
+func (b jsonSchemaBuilder) primitive(schema *jsonObject, s *Primitive) {
+	if s.Coerce && s.Kind != PrimitiveString {
+		// the value is still a string in the JSON, Zod converts it afterwards.
+		if s.Kind == PrimitiveBoolean {
+			schema.set("enum", []string{"true", "false"})
+		} else {
+			schema.set("type", "string")
+		}
+		return
+	}
+
+	patterns := []string{}
+	switch s.Kind {
+	case PrimitiveString:
+		schema.set("type", "string")
+		switch s.Format {
+		case "base64":
+			schema.set("contentEncoding", "base64")
+		case "":
+		default:
+			schema.set("format", s.Format)
+		}
+		for _, check := range s.Checks {
+			switch check.Kind {
+			case CheckMin:
+				schema.set("minLength", json.Number(check.Value))
+			case CheckMax:
+				schema.set("maxLength", json.Number(check.Value))
+			case CheckLength:
+				schema.set("minLength", json.Number(check.Value))
+				schema.set("maxLength", json.Number(check.Value))
+			case CheckEmail, CheckURL, CheckUUID:
+				schema.set("format", jsonSchemaFormats[check.Kind])
+			case CheckIP:
+				if check.Value == "" {
+					v4, v6 := &jsonObject{}, &jsonObject{}
+					v4.set("format", "ipv4")
+					v6.set("format", "ipv6")
+					schema.set("anyOf", []interface{}{v4, v6})
+				} else {
+					schema.set("format", "ip"+check.Value)
+				}
+			case CheckRegex:
+				patterns = append(patterns, check.Value)
+			case CheckStartsWith:
+				patterns = append(patterns, "^"+regexp.QuoteMeta(check.Value))
+			case CheckEndsWith:
+				patterns = append(patterns, regexp.QuoteMeta(check.Value)+"$")
+			case CheckIncludes:
+				patterns = append(patterns, regexp.QuoteMeta(check.Value))
+			}
+		}
+
+	case PrimitiveNumber, PrimitiveBigInt:
+		// bigints are still numbers in the JSON, they are only parsed
+		// differently.
+		typ := "number"
+		for _, check := range s.Checks {
+			if check.Kind == CheckInt || s.Kind == PrimitiveBigInt {
+				typ = "integer"
+			}
+		}
+		schema.set("type", typ)
+		for _, check := range s.Checks {
+			switch check.Kind {
+			case CheckNonNegative:
+				schema.set("minimum", 0)
+			case CheckNonZero:
+				zero := &jsonObject{}
+				zero.set("const", 0)
+				schema.set("not", zero)
+			default:
+				if keyword, ok := jsonSchemaNumberChecks[check.Kind]; ok {
+					schema.set(keyword, json.Number(check.Value))
+				}
+			}
+		}
+
+	case PrimitiveBoolean:
+		schema.set("type", "boolean")
+		for _, check := range s.Checks {
+			if check.Kind == CheckTrue {
+				schema.set("const", true)
+			}
+		}
+	}
+
+	// a schema can only have one pattern, any others must also match.
+	if len(patterns) > 0 {
+		schema.set("pattern", patterns[0])
+	}
+	if len(patterns) > 1 {
+		all := make([]interface{}, len(patterns)-1)
+		for i, pattern := range patterns[1:] {
+			match := &jsonObject{}
+			match.set("pattern", pattern)
+			all[i] = match
+		}
+		schema.set("allOf", all)
+	}
+}
+
+// checks whether the schema of a map key can be used for property names, which
+// are always strings. other keys, such as integers, are written as strings by
+// encoding/json so their schema does not apply.
+func isStringKey(s Schema) bool {
+	switch s := s.(type) {
+	case *Primitive:
+		return s.Kind == PrimitiveString && len(s.Checks) > 0
+	case *Enum, *Reference:
+		return true
+	}
+	return false
+}
+
+// allows null as well as a schema, by adding it to the type where there is one.
+func nullable(schema *jsonObject) *jsonObject {
+	if typ, ok := schema.values["type"].(string); ok {
+		if _, isEnum := schema.values["enum"]; !isEnum {
+			schema.set("type", []string{typ, "null"})
+			return schema
+		}
+	}
+
+	null := &jsonObject{}
+	null.set("type", "null")
+	either := &jsonObject{}
+	either.set("anyOf", []interface{}{schema, null})
+	return either
+}
+
+// a JSON object that keeps its keys in the order they were first set, so that
+// the output reads in the same order as the Go types.
+type jsonObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (o *jsonObject) set(key string, value interface{}) {
+	if o.values == nil {
+		o.values = map[string]interface{}{}
+	}
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// copies the keys of the object, but not the values they hold, so that keys can
+// be added to the copy alone.
+func (o *jsonObject) copy() *jsonObject {
+	c := &jsonObject{}
+	for _, key := range o.keys {
+		c.set(key, o.values[key])
+	}
+	return c
+}
+
+// reads a JSON Schema given as a string, keeping the order of its keys. schemas
+// that are not objects, such as `true`, are nested in an `allOf`.
+func parseJSONObject(s string) *jsonObject {
+	o := &jsonObject{}
+
+	d := json.NewDecoder(strings.NewReader(s))
+	if t, err := d.Token(); err == nil && t == json.Delim('{') {
+		for d.More() {
+			key, err := d.Token()
+			if err != nil {
+				break
+			}
+			var value json.RawMessage
+			if err := d.Decode(&value); err != nil {
+				break
+			}
+			o.set(key.(string), value)
+		}
+		return o
+	}
+
+	o.set("allOf", []json.RawMessage{json.RawMessage(s)})
+	return o
+}
+
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	output := bytes.Buffer{}
+	output.WriteString("{")
+	for i, key := range o.keys {
+		if i > 0 {
+			output.WriteString(",")
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", strings.Trim(string(k), `"`), err)
+		}
+		output.Write(k)
+		output.WriteString(":")
+		output.Write(v)
+	}
+	output.WriteString("}")
+	return output.Bytes(), nil
+}
@@ -0,0 +1,234 @@
+package supervillain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Money struct{}
+
+func (Money) ZodSchema() string  { return "z.string()" }
+func (Money) JSONSchema() string { return `{"type": "string", "pattern": "^\\d+\\.\\d{2}$"}` }
+
+type Parcel struct {
+	SKU      string   `json:"sku" validate:"required,startswith=SKU-"`
+	Quantity uint8    `json:"quantity"`
+	Price    Money    `json:"price"`
+	Notes    *string  `json:"notes"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+type Shipment struct {
+	Parcels []Parcel       `json:"parcels"`
+	Coupons map[string]int `json:"coupons"`
+	Box     Page[Parcel]   `json:"box"`
+	Parent  *Shipment      `json:"parent,omitempty"`
+}
+
+func TestConvertJSONSchema(t *testing.T) {
+	c := NewConverter(nil, WithValidationTags("validate"))
+	assert.Equal(t, `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$ref": "#/$defs/Shipment",
+  "$defs": {
+    "Parcel": {
+      "type": "object",
+      "properties": {
+        "sku": {
+          "type": "string",
+          "minLength": 1,
+          "pattern": "^SKU-"
+        },
+        "quantity": {
+          "type": "integer",
+          "minimum": 0,
+          "maximum": 255
+        },
+        "price": {
+          "type": "string",
+          "pattern": "^\\d+\\.\\d{2}$"
+        },
+        "notes": {
+          "type": [
+            "string",
+            "null"
+          ]
+        },
+        "tags": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      },
+      "required": [
+        "sku",
+        "quantity",
+        "price",
+        "notes"
+      ]
+    },
+    "Shipment": {
+      "type": "object",
+      "properties": {
+        "parcels": {
+          "type": [
+            "array",
+            "null"
+          ],
+          "items": {
+            "$ref": "#/$defs/Parcel"
+          }
+        },
+        "coupons": {
+          "type": [
+            "object",
+            "null"
+          ],
+          "additionalProperties": {
+            "type": "integer"
+          }
+        },
+        "box": {
+          "type": "object",
+          "properties": {
+            "Items": {
+              "type": [
+                "array",
+                "null"
+              ],
+              "items": {
+                "$ref": "#/$defs/Parcel"
+              }
+            },
+            "Next": {
+              "type": "string"
+            }
+          },
+          "required": [
+            "Items",
+            "Next"
+          ]
+        },
+        "parent": {
+          "$ref": "#/$defs/Shipment"
+        }
+      },
+      "required": [
+        "parcels",
+        "coupons",
+        "box"
+      ]
+    }
+  }
+}
+`, c.ConvertJSONSchema([]interface{}{Shipment{}}))
+}
+
+type Timeline struct {
+	Latest   Event    `json:"latest"`
+	Priority Priority `json:"priority"`
+	State    State    `json:"state,omitempty"`
+}
+
+func TestConvertJSONSchemaUnionsAndEnums(t *testing.T) {
+	c := NewConverter(nil, WithCustomJSONSchemas(map[string]string{
+		"github.com/Southclaws/supervillain.State": `{"type": "string"}`,
+	}))
+	require.NoError(t, c.RegisterUnion((*Event)(nil), "type", Created{Type: "created"}, &Deleted{Type: "deleted"}))
+	require.NoError(t, c.RegisterEnum(PriorityLow, PriorityMedium, PriorityHigh))
+
+	assert.Equal(t, `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$ref": "#/$defs/Timeline",
+  "$defs": {
+    "Created": {
+      "type": "object",
+      "properties": {
+        "type": {
+          "const": "created"
+        },
+        "id": {
+          "type": "string"
+        }
+      },
+      "required": [
+        "type",
+        "id"
+      ]
+    },
+    "Deleted": {
+      "type": "object",
+      "properties": {
+        "type": {
+          "const": "deleted"
+        },
+        "id": {
+          "type": "string"
+        },
+        "reason": {
+          "type": "string"
+        }
+      },
+      "required": [
+        "type",
+        "id"
+      ]
+    },
+    "Event": {
+      "oneOf": [
+        {
+          "$ref": "#/$defs/Created"
+        },
+        {
+          "$ref": "#/$defs/Deleted"
+        }
+      ]
+    },
+    "Priority": {
+      "enum": [
+        0,
+        1,
+        2
+      ]
+    },
+    "Timeline": {
+      "type": "object",
+      "properties": {
+        "latest": {
+          "anyOf": [
+            {
+              "$ref": "#/$defs/Event"
+            },
+            {
+              "type": "null"
+            }
+          ]
+        },
+        "priority": {
+          "$ref": "#/$defs/Priority"
+        },
+        "state": {
+          "type": "string"
+        }
+      },
+      "required": [
+        "latest",
+        "priority"
+      ]
+    }
+  }
+}
+`, c.ConvertJSONSchema([]interface{}{Timeline{}}))
+}
+
+func TestCustomJSONSchemaInvalid(t *testing.T) {
+	c := NewConverter(nil, WithCustomJSONSchemas(map[string]string{
+		"github.com/Southclaws/supervillain.State": `{"type": "string"`,
+	}))
+
+	_, err := c.ConvertJSONSchemaE([]interface{}{Timeline{}})
+	assert.EqualError(t, err, `Timeline.State: JSON Schema of github.com/Southclaws/supervillain.State is not valid JSON: {"type": "string" (invalid_json_schema)`)
+}
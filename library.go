@@ -0,0 +1,117 @@
+package supervillain
+
+import (
+	"reflect"
+)
+
+// Library is a schema library that declarations are printed for, such as
+// ZodPrinter for Zod. ConvertLibrary and ConvertLibraryModules write out the
+// schemas with any library, so that each one only has to print declarations.
+type Library interface {
+	// Name identifies the library, such as "valibot". Custom types give their
+	// schemas for the library under this name.
+	Name() string
+	// Declaration prints the schema of a declaration, and usually its type.
+	Declaration(d *Declaration) string
+	// Import prints the statement that imports the library, given the
+	// preamble.
+	Import(p Preamble) string
+}
+
+// ConstantLibrarySchemas is implemented by custom types that describe
+// themselves for libraries other than Zod, alongside their ZodSchema method.
+// The schemas are keyed by the name of the library, such as "valibot". Without
+// a schema for a library, custom types accept any value in it.
+type ConstantLibrarySchemas interface {
+	LibrarySchemas() map[string]string
+}
+
+type customLibrarySchemasOption struct {
+	library string
+	schemas map[string]string
+}
+
+func (l customLibrarySchemasOption) apply(c *Converter) {
+	if c.librarySchemas == nil {
+		c.librarySchemas = map[string]map[string]string{}
+	}
+	c.librarySchemas[l.library] = l.schemas
+}
+
+// WithCustomLibrarySchemas gives the schemas of custom types for the library
+// with the given name, keyed by their full name as in the map of CustomFn given
+// to NewConverter, such as `github.com/shopspring/decimal.Decimal`. It can be
+// given once for each library.
+func WithCustomLibrarySchemas(library string, schemas map[string]string) Option {
+	return customLibrarySchemasOption{library, schemas}
+}
+
+// gets the schemas that a custom type supplies for libraries other than Zod,
+// from its LibrarySchemas method and then the schemas given by its full name.
+func (c *Converter) customLibrarySchemas(t reflect.Type) map[string]string {
+	schemas := map[string]string{}
+	for _, v := range []interface{}{reflect.Zero(t).Interface(), reflect.Zero(reflect.PointerTo(t)).Interface()} {
+		if v, ok := v.(ConstantLibrarySchemas); ok {
+			for library, schema := range v.LibrarySchemas() {
+				schemas[library] = schema
+			}
+			break
+		}
+	}
+
+	fullName, _ := getFullName(t)
+	for library, custom := range c.librarySchemas {
+		if schema, ok := custom[fullName]; ok {
+			schemas[library] = schema
+		}
+	}
+
+	if len(schemas) == 0 {
+		return nil
+	}
+	return schemas
+}
+
+// ConvertLibrary converts the inputs like ConvertSlice, but prints them for
+// the given library. The names and order of the schemas are the same whatever
+// the library, so the output of one can be swapped for another.
+func (c *Converter) ConvertLibrary(l Library, inputs []interface{}) string {
+	for _, input := range inputs {
+		c.convertTopLevel(reflect.TypeOf(input))
+	}
+
+	return c.render(l)
+}
+
+// ConvertLibraryE is like ConvertLibrary but returns every problem found in
+// the types as a ConversionErrors instead of panicking on the first one.
+func (c *Converter) ConvertLibraryE(l Library, inputs []interface{}) (string, error) {
+	return c.collectErrors(func() string {
+		return c.ConvertLibrary(l, inputs)
+	})
+}
+
+// ConvertLibraryModules is like ConvertModules but prints the schemas for the
+// given library, which each module imports.
+func (c *Converter) ConvertLibraryModules(l Library, inputs []interface{}) map[string]string {
+	for _, input := range inputs {
+		c.convertTopLevel(reflect.TypeOf(input))
+	}
+
+	return c.renderModules(l)
+}
+
+// ConvertLibraryModulesE is like ConvertLibraryModules but returns every
+// problem found in the types as a ConversionErrors instead of panicking on the
+// first one.
+func (c *Converter) ConvertLibraryModulesE(l Library, inputs []interface{}) (map[string]string, error) {
+	modules := map[string]string{}
+	_, err := c.collectErrors(func() string {
+		modules = c.ConvertLibraryModules(l, inputs)
+		return ""
+	})
+	if err != nil {
+		return nil, err
+	}
+	return modules, nil
+}
@@ -0,0 +1,467 @@
+package supervillain
+
+import (
+	"testing"
+
+	"github.com/Southclaws/supervillain/internal/fixtures/shop/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Amount gives its schemas for other libraries itself.
+type Amount struct{}
+
+func (Amount) ZodSchema() string { return "z.string()" }
+
+func (Amount) LibrarySchemas() map[string]string {
+	return map[string]string{
+		"valibot": `v.pipe(v.string(), v.decimal())`,
+		"typebox": `Type.String({ pattern: "^\\d+\\.\\d{2}$" })`,
+		"effect":  `Schema.BigDecimal`,
+	}
+}
+
+// Stage has its schemas for other libraries given by WithCustomLibrarySchemas.
+type Stage string
+
+func (Stage) ZodSchema() string { return `z.enum(["draft", "sent"])` }
+
+type Batch[T any] struct {
+	Items  []T
+	Cursor string
+}
+
+type BillLine struct {
+	Product  string `json:"product" validate:"required,startswith=SKU-"`
+	Quantity uint8  `json:"quantity"`
+}
+
+type Bill struct {
+	Total    Amount          `json:"total"`
+	Stage    Stage           `json:"stage,omitempty"`
+	Memo     *string         `json:"memo"`
+	Taxes    map[string]int  `json:"taxes"`
+	Lines    Batch[BillLine] `json:"lines"`
+	Replaces *Bill           `json:"replaces,omitempty"`
+}
+
+type Payment interface {
+	isPayment()
+}
+
+type Card struct {
+	Method string `json:"method"`
+	Last4  string `json:"last4"`
+}
+
+func (Card) isPayment() {}
+
+type Transfer struct {
+	Method    string `json:"method"`
+	Reference string `json:"reference,omitempty"`
+}
+
+func (*Transfer) isPayment() {}
+
+type Urgency int
+
+const (
+	UrgencyLow Urgency = iota
+	UrgencyHigh
+)
+
+type Remittance struct {
+	Payment Payment `json:"payment"`
+	Urgency Urgency `json:"urgency"`
+}
+
+func TestConvertLibrary(t *testing.T) {
+	for _, tc := range []struct {
+		library Library
+		want    string
+	}{
+		{ValibotPrinter{}, `export const BatchSchema = <T extends v.GenericSchema>(t: T) => v.object({
+  Items: v.nullable(v.array(t)),
+  Cursor: v.string(),
+})
+export type Batch<T extends v.GenericSchema> = v.InferOutput<ReturnType<typeof BatchSchema<T>>>
+
+export const BillLineSchema = v.object({
+  product: v.pipe(v.string(), v.minLength(1), v.startsWith("SKU-")),
+  quantity: v.pipe(v.number(), v.integer(), v.minValue(0), v.maxValue(255)),
+})
+export type BillLine = v.InferOutput<typeof BillLineSchema>
+
+export type Bill = {
+  total: unknown
+  stage?: unknown
+  memo: string | null
+  taxes: Record<string, number> | null
+  lines: Batch<v.GenericSchema<BillLine>>
+  replaces?: Bill
+}
+export const BillSchema: v.GenericSchema<Bill> = v.object({
+  total: v.pipe(v.string(), v.decimal()),
+  stage: v.optional(v.picklist(["draft", "sent"])),
+  memo: v.nullable(v.string()),
+  taxes: v.nullable(v.record(v.string(), v.pipe(v.number(), v.integer()))),
+  lines: BatchSchema(BillLineSchema),
+  replaces: v.optional(v.lazy(() => BillSchema)),
+})
+
+`},
+		{TypeBoxPrinter{}, `export const BatchSchema = <T extends TSchema>(t: T) => Type.Object({
+  Items: Type.Union([Type.Array(t), Type.Null()]),
+  Cursor: Type.String(),
+})
+export type Batch<T extends TSchema> = Static<ReturnType<typeof BatchSchema<T>>>
+
+export const BillLineSchema = Type.Object({
+  product: Type.String({ minLength: 1, pattern: "^SKU-" }),
+  quantity: Type.Integer({ minimum: 0, maximum: 255 }),
+})
+export type BillLine = Static<typeof BillLineSchema>
+
+export type Bill = {
+  total: unknown
+  stage?: unknown
+  memo: string | null
+  taxes: Record<string, number> | null
+  lines: Batch<TSchema & { static: BillLine }>
+  replaces?: Bill
+}
+export const BillSchema = Type.Recursive((This) => Type.Object({
+  total: Type.String({ pattern: "^\\d+\\.\\d{2}$" }),
+  stage: Type.Optional(Type.Union([Type.Literal("draft"), Type.Literal("sent")])),
+  memo: Type.Union([Type.String(), Type.Null()]),
+  taxes: Type.Union([Type.Record(Type.String(), Type.Integer()), Type.Null()]),
+  lines: BatchSchema(BillLineSchema),
+  replaces: Type.Optional(This),
+}), { $id: "Bill" })
+
+`},
+		{EffectPrinter{}, `export const BatchSchema = <T extends Schema.Schema.Any>(t: T) => Schema.Struct({
+  Items: Schema.NullOr(Schema.Array(t)),
+  Cursor: Schema.String,
+})
+export type Batch<T extends Schema.Schema.Any> = Schema.Schema.Type<ReturnType<typeof BatchSchema<T>>>
+
+export const BillLineSchema = Schema.Struct({
+  product: Schema.String.pipe(Schema.minLength(1), Schema.startsWith("SKU-")),
+  quantity: Schema.Number.pipe(Schema.int(), Schema.greaterThanOrEqualTo(0), Schema.lessThanOrEqualTo(255)),
+})
+export type BillLine = typeof BillLineSchema.Type
+
+export type Bill = {
+  readonly total: unknown
+  readonly stage?: unknown
+  readonly memo: string | null
+  readonly taxes: Readonly<Record<string, number>> | null
+  readonly lines: Batch<Schema.Schema<BillLine>>
+  readonly replaces?: Bill
+}
+export const BillSchema: Schema.Schema<Bill> = Schema.Struct({
+  total: Schema.BigDecimal,
+  stage: Schema.optional(Schema.Literal("draft", "sent")),
+  memo: Schema.NullOr(Schema.String),
+  taxes: Schema.NullOr(Schema.Record({ key: Schema.String, value: Schema.Number.pipe(Schema.int()) })),
+  lines: BatchSchema(BillLineSchema),
+  replaces: Schema.optional(Schema.suspend(() => BillSchema)),
+})
+
+`},
+	} {
+		t.Run(tc.library.Name(), func(t *testing.T) {
+			c := NewConverter(nil, WithValidationTags("validate"),
+				WithCustomLibrarySchemas("valibot", map[string]string{
+					"github.com/Southclaws/supervillain.Stage": `v.picklist(["draft", "sent"])`,
+				}),
+				WithCustomLibrarySchemas("typebox", map[string]string{
+					"github.com/Southclaws/supervillain.Stage": `Type.Union([Type.Literal("draft"), Type.Literal("sent")])`,
+				}),
+				WithCustomLibrarySchemas("effect", map[string]string{
+					"github.com/Southclaws/supervillain.Stage": `Schema.Literal("draft", "sent")`,
+				}),
+			)
+			assert.Equal(t, tc.want, c.ConvertLibrary(tc.library, []interface{}{Bill{}}))
+		})
+	}
+}
+
+func TestConvertLibraryUnionsAndEnums(t *testing.T) {
+	for _, tc := range []struct {
+		library Library
+		want    string
+	}{
+		{ValibotPrinter{}, `export const CardSchema = v.object({
+  method: v.literal("card"),
+  last4: v.string(),
+})
+export type Card = v.InferOutput<typeof CardSchema>
+
+export const TransferSchema = v.object({
+  method: v.literal("transfer"),
+  reference: v.optional(v.string()),
+})
+export type Transfer = v.InferOutput<typeof TransferSchema>
+
+export const PaymentSchema = v.variant("method", [CardSchema, TransferSchema])
+export type Payment = v.InferOutput<typeof PaymentSchema>
+
+export const UrgencySchema = v.union([v.literal(0), v.literal(1)])
+export type Urgency = v.InferOutput<typeof UrgencySchema>
+
+export const RemittanceSchema = v.object({
+  payment: v.nullable(PaymentSchema),
+  urgency: UrgencySchema,
+})
+export type Remittance = v.InferOutput<typeof RemittanceSchema>
+
+`},
+		{TypeBoxPrinter{}, `export const CardSchema = Type.Object({
+  method: Type.Literal("card"),
+  last4: Type.String(),
+})
+export type Card = Static<typeof CardSchema>
+
+export const TransferSchema = Type.Object({
+  method: Type.Literal("transfer"),
+  reference: Type.Optional(Type.String()),
+})
+export type Transfer = Static<typeof TransferSchema>
+
+export const PaymentSchema = Type.Union([CardSchema, TransferSchema])
+export type Payment = Static<typeof PaymentSchema>
+
+export const UrgencySchema = Type.Union([Type.Literal(0), Type.Literal(1)])
+export type Urgency = Static<typeof UrgencySchema>
+
+export const RemittanceSchema = Type.Object({
+  payment: Type.Union([PaymentSchema, Type.Null()]),
+  urgency: UrgencySchema,
+})
+export type Remittance = Static<typeof RemittanceSchema>
+
+`},
+		{EffectPrinter{}, `export const CardSchema = Schema.Struct({
+  method: Schema.Literal("card"),
+  last4: Schema.String,
+})
+export type Card = typeof CardSchema.Type
+
+export const TransferSchema = Schema.Struct({
+  method: Schema.Literal("transfer"),
+  reference: Schema.optional(Schema.String),
+})
+export type Transfer = typeof TransferSchema.Type
+
+export const PaymentSchema = Schema.Union(CardSchema, TransferSchema)
+export type Payment = typeof PaymentSchema.Type
+
+export const UrgencySchema = Schema.Literal(0, 1)
+export type Urgency = typeof UrgencySchema.Type
+
+export const RemittanceSchema = Schema.Struct({
+  payment: Schema.NullOr(PaymentSchema),
+  urgency: UrgencySchema,
+})
+export type Remittance = typeof RemittanceSchema.Type
+
+`},
+	} {
+		t.Run(tc.library.Name(), func(t *testing.T) {
+			c := NewConverter(nil)
+			require.NoError(t, c.RegisterUnion((*Payment)(nil), "method", Card{Method: "card"}, &Transfer{Method: "transfer"}))
+			require.NoError(t, c.RegisterEnum(UrgencyLow, UrgencyHigh))
+			assert.Equal(t, tc.want, c.ConvertLibrary(tc.library, []interface{}{Remittance{}}))
+		})
+	}
+}
+
+func TestConvertLibraryFields(t *testing.T) {
+	type Payer struct {
+		Name string
+	}
+	type Account struct {
+		Email   string  `json:"email" validate:"email,lowercase"`
+		Address string  `json:"address" validate:"ip"`
+		Count   int     `json:"count,string"`
+		Enabled bool    `json:"enabled,string"`
+		Nick    *string `json:"nick,omitempty"`
+		Role    string  `json:"role" zod:"default=\"member\",describe=What they can do"`
+		Payer   *Payer  `json:"payer" zod:"nullish,describe=Who pays"`
+	}
+
+	for _, tc := range []struct {
+		library Library
+		want    string
+	}{
+		{ValibotPrinter{}, `import * as v from "valibot"
+
+export const PayerSchema = v.object({
+  Name: v.string(),
+})
+export type Payer = v.InferOutput<typeof PayerSchema>
+
+export const AccountSchema = v.object({
+  email: v.pipe(v.string(), v.email(), v.check((s) => s === s.toLowerCase())),
+  address: v.pipe(v.string(), v.ip()),
+  count: v.pipe(v.string(), v.transform(Number), v.number(), v.integer()),
+  enabled: v.pipe(v.picklist(["true", "false"]), v.transform((s) => s === "true")),
+  nick: v.optional(v.string()),
+  role: v.optional(v.pipe(v.string(), v.description("What they can do")), "member"),
+  payer: v.nullish(v.pipe(PayerSchema, v.description("Who pays"))),
+})
+export type Account = v.InferOutput<typeof AccountSchema>
+
+`},
+		{TypeBoxPrinter{}, `import { Type, type Static, type TSchema } from "@sinclair/typebox"
+
+export const PayerSchema = Type.Object({
+  Name: Type.String(),
+})
+export type Payer = Static<typeof PayerSchema>
+
+export const AccountSchema = Type.Object({
+  email: Type.String({ format: "email" }),
+  address: Type.String({ anyOf: [{ format: "ipv4" }, { format: "ipv6" }] }),
+  count: Type.String(),
+  enabled: Type.Union([Type.Literal("true"), Type.Literal("false")]),
+  nick: Type.Optional(Type.String()),
+  role: Type.String({ default: "member", description: "What they can do" }),
+  payer: Type.Optional(Type.Union([PayerSchema, Type.Null()], { description: "Who pays" })),
+})
+export type Account = Static<typeof AccountSchema>
+
+`},
+		{EffectPrinter{}, `import { Schema } from "effect"
+
+export const PayerSchema = Schema.Struct({
+  Name: Schema.String,
+})
+export type Payer = typeof PayerSchema.Type
+
+export const AccountSchema = Schema.Struct({
+  email: Schema.String.pipe(Schema.pattern(/^[^\s@]+@[^\s@]+\.[^\s@]+$/), Schema.lowercased()),
+  address: Schema.String.pipe(Schema.filter((s) => /^((25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(25[0-5]|2[0-4]\d|1?\d?\d)$/.test(s) || /^(([0-9a-f]{1,4}:){7}[0-9a-f]{1,4}|(([0-9a-f]{1,4}:)*[0-9a-f]{1,4})?::(([0-9a-f]{1,4}:)*[0-9a-f]{1,4})?)$/i.test(s))),
+  count: Schema.NumberFromString.pipe(Schema.int()),
+  enabled: Schema.transform(Schema.Literal("true", "false"), Schema.Boolean, { strict: true, decode: (s) => s === "true", encode: (b) => (b ? "true" : "false") }),
+  nick: Schema.optional(Schema.String),
+  role: Schema.optionalWith(Schema.String.annotations({ description: "What they can do" }), { default: () => "member" }),
+  payer: Schema.optional(Schema.NullOr(PayerSchema.annotations({ description: "Who pays" }))),
+})
+export type Account = typeof AccountSchema.Type
+
+`},
+	} {
+		t.Run(tc.library.Name(), func(t *testing.T) {
+			c := NewConverter(nil, WithValidationTags("validate"), WithQuotedMode(QuotedCoerce), WithPreamble(Preamble{}))
+			assert.Equal(t, tc.want, c.ConvertLibrary(tc.library, []interface{}{Account{}}))
+		})
+	}
+}
+
+func TestConvertLibraryModules(t *testing.T) {
+	for _, tc := range []struct {
+		library Library
+		want    map[string]string
+	}{
+		{ValibotPrinter{}, map[string]string{
+			"people.ts": `import * as v from "valibot"
+
+export const PersonSchema = v.object({
+  Name: v.string(),
+})
+export type Person = v.InferOutput<typeof PersonSchema>
+
+`,
+			"shop/orders.ts": `import * as v from "valibot"
+import { type Person, PersonSchema } from "../people"
+
+export const LineSchema = v.object({
+  Product: v.string(),
+})
+export type Line = v.InferOutput<typeof LineSchema>
+
+export type Order = {
+  Buyer: Person
+  Lines: Line[] | null
+  Parent: Order | null
+}
+export const OrderSchema: v.GenericSchema<Order> = v.object({
+  Buyer: PersonSchema,
+  Lines: v.nullable(v.array(LineSchema)),
+  Parent: v.nullable(v.lazy(() => OrderSchema)),
+})
+
+`,
+		}},
+		{TypeBoxPrinter{}, map[string]string{
+			"people.ts": `import { Type, type Static, type TSchema } from "@sinclair/typebox"
+
+export const PersonSchema = Type.Object({
+  Name: Type.String(),
+})
+export type Person = Static<typeof PersonSchema>
+
+`,
+			"shop/orders.ts": `import { Type, type Static, type TSchema } from "@sinclair/typebox"
+import { type Person, PersonSchema } from "../people"
+
+export const LineSchema = Type.Object({
+  Product: Type.String(),
+})
+export type Line = Static<typeof LineSchema>
+
+export type Order = {
+  Buyer: Person
+  Lines: Line[] | null
+  Parent: Order | null
+}
+export const OrderSchema = Type.Recursive((This) => Type.Object({
+  Buyer: PersonSchema,
+  Lines: Type.Union([Type.Array(LineSchema), Type.Null()]),
+  Parent: Type.Union([This, Type.Null()]),
+}), { $id: "Order" })
+
+`,
+		}},
+		{EffectPrinter{}, map[string]string{
+			"people.ts": `import { Schema } from "effect"
+
+export const PersonSchema = Schema.Struct({
+  Name: Schema.String,
+})
+export type Person = typeof PersonSchema.Type
+
+`,
+			"shop/orders.ts": `import { Schema } from "effect"
+import { type Person, PersonSchema } from "../people"
+
+export const LineSchema = Schema.Struct({
+  Product: Schema.String,
+})
+export type Line = typeof LineSchema.Type
+
+export type Order = {
+  readonly Buyer: Person
+  readonly Lines: ReadonlyArray<Line> | null
+  readonly Parent: Order | null
+}
+export const OrderSchema: Schema.Schema<Order> = Schema.Struct({
+  Buyer: PersonSchema,
+  Lines: Schema.NullOr(Schema.Array(LineSchema)),
+  Parent: Schema.NullOr(Schema.suspend(() => OrderSchema)),
+})
+
+`,
+		}},
+	} {
+		t.Run(tc.library.Name(), func(t *testing.T) {
+			c := NewConverter(nil, WithModuleLayout(ModuleLayout{
+				Root: "github.com/Southclaws/supervillain/internal/fixtures",
+			}))
+			assert.Equal(t, tc.want, c.ConvertLibraryModules(tc.library, []interface{}{orders.Order{}}))
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package supervillain
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ModuleLayout configures how ConvertModules splits schemas into TypeScript
+// modules. By default there is one module for each Go package.
+type ModuleLayout struct {
+	// Root is the package path that module paths are relative to, so with a
+	// root of `github.com/acme/api` the types in `github.com/acme/api/users`
+	// are written to `users.ts`. Types in the root package itself are written
+	// to a module named after it and types outside of it to a module at their
+	// full package path.
+	Root string
+	// Group replaces the grouping by package. It is given each named type and
+	// returns the path of its module without an extension, such as
+	// `billing/invoices`.
+	Group func(t reflect.Type) string
+	// Index adds an `index.ts` module that re-exports every other module.
+	Index bool
+	// ImportExtension is added to the paths of imports between modules, such as
+	// `.js` for ES modules in Node.
+	ImportExtension string
+}
+
+type moduleLayoutOption ModuleLayout
+
+func (m moduleLayoutOption) apply(c *Converter) {
+	c.moduleLayout = ModuleLayout(m)
+}
+
+// WithModuleLayout sets how ConvertModules splits schemas into modules.
+func WithModuleLayout(l ModuleLayout) Option {
+	return moduleLayoutOption(l)
+}
+
+// what one schema refers to in another, which decides what is imported.
+type reference int
+
+const (
+	// the schema, such as `UserSchema`.
+	referenceSchema reference = 1 << iota
+	// the TypeScript type, such as `User`, from a hand-written declaration.
+	referenceType
+)
+
+// records that the schema at the top of the stack refers to the schema or type
+// of another.
+func (c *Converter) addReference(key string, kind reference) {
+	if len(c.stack) == 0 {
+		return
+	}
+	c.addReferenceFrom(c.stack[len(c.stack)-1], key, kind)
+}
+
+// records that one schema refers to the schema or type of another.
+func (c *Converter) addReferenceFrom(from, key string, kind reference) {
+	if from == key {
+		return
+	}
+	if c.refs == nil {
+		c.refs = make(map[string]map[string]reference)
+	}
+	if c.refs[from] == nil {
+		c.refs[from] = make(map[string]reference)
+	}
+	c.refs[from][key] |= kind
+}
+
+// ConvertModules converts the inputs like ConvertSlice, but splits the schemas
+// into TypeScript modules instead of writing them all to one string. The
+// modules import zod, as configured by WithPreamble, and the schemas they use
+// from each other, and are returned keyed by their file path, such as
+// `users.ts`.
+//
+// Imports between modules follow references between schemas. Go packages
+// cannot import each other, but modules grouped with ModuleLayout.Group can end
+// up doing so, which JavaScript only allows when the schemas that close the
+// cycle are referenced lazily.
+func (c *Converter) ConvertModules(inputs []interface{}) map[string]string {
+	return c.ConvertLibraryModules(c.zodPrinter(), inputs)
+}
+
+// ConvertModulesE is like ConvertModules but returns every problem found in the
+// types as a ConversionErrors instead of panicking on the first one.
+func (c *Converter) ConvertModulesE(inputs []interface{}) (map[string]string, error) {
+	modules := map[string]string{}
+	_, err := c.collectErrors(func() string {
+		modules = c.ConvertModules(inputs)
+		return ""
+	})
+	if err != nil {
+		return nil, err
+	}
+	return modules, nil
+}
+
+// gets the path of the module, without an extension, that a type is written
+// to.
+func (l ModuleLayout) module(t reflect.Type) string {
+	if l.Group != nil {
+		return l.Group(t)
+	}
+
+	path := t.PkgPath()
+	switch {
+	case l.Root != "" && path == l.Root:
+		return path[strings.LastIndex(path, "/")+1:]
+	case l.Root != "" && strings.HasPrefix(path, l.Root+"/"):
+		return strings.TrimPrefix(path, l.Root+"/")
+	}
+	return path
+}
+
+// writes out every schema to its module, along with the imports it needs.
+func (c *Converter) renderModules(l Library) map[string]string {
+	modules := map[string]map[string]entry{}
+	moduleOf := map[string]string{}
+	for key, e := range c.outputs {
+		module := c.moduleLayout.module(c.types[key])
+		if modules[module] == nil {
+			modules[module] = map[string]entry{}
+		}
+		modules[module][key] = e
+		moduleOf[key] = module
+	}
+
+	preamble := c.preambleOrDefault()
+	c.checkImport(l, preamble)
+	files := map[string]string{}
+	for module, outputs := range modules {
+		output := strings.Builder{}
+		output.WriteString(preamble.header(sources(outputs)))
+		output.WriteString(l.Import(preamble))
+		for _, imp := range c.moduleImports(module, outputs, moduleOf) {
+			output.WriteString(imp)
+			output.WriteString("\n")
+		}
+		output.WriteString("\n")
+
+		for _, d := range c.declarations(outputs) {
+			output.WriteString(l.Declaration(d))
+			output.WriteString("\n\n")
+		}
+		files[module+".ts"] = output.String()
+	}
+
+	if c.moduleLayout.Index {
+		names := make([]string, 0, len(modules))
+		for module := range modules {
+			names = append(names, module)
+		}
+		sort.Strings(names)
+
+		output := strings.Builder{}
+		output.WriteString(preamble.header(nil))
+		for _, module := range names {
+			output.WriteString(fmt.Sprintf("export * from %s\n", jsString(importPath("index", module, c.moduleLayout.ImportExtension))))
+		}
+		files["index.ts"] = output.String()
+	}
+
+	return files
+}
+
+// builds the import statements for the schemas and types that the schemas in a
+// module use from other modules, sorted by the path they are imported from.
+func (c *Converter) moduleImports(module string, outputs map[string]entry, moduleOf map[string]string) []string {
+	imported := map[string]map[string]bool{}
+	for key := range outputs {
+		for ref, kind := range c.refs[key] {
+			from, ok := moduleOf[ref]
+			if !ok || from == module {
+				continue
+			}
+			if imported[from] == nil {
+				imported[from] = map[string]bool{}
+			}
+			name := c.names[ref]
+			if kind&referenceSchema != 0 {
+				imported[from][schemaName(c.prefix, name)] = true
+			}
+			if kind&referenceType != 0 {
+				imported[from]["type "+c.prefix+name] = true
+			}
+		}
+	}
+
+	froms := make([]string, 0, len(imported))
+	for from := range imported {
+		froms = append(froms, from)
+	}
+	sort.Strings(froms)
+
+	imports := []string{}
+	for _, from := range froms {
+		names := make([]string, 0, len(imported[from]))
+		for name := range imported[from] {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			return strings.TrimPrefix(names[i], "type ") < strings.TrimPrefix(names[j], "type ")
+		})
+		imports = append(imports, fmt.Sprintf("import { %s } from %s",
+			strings.Join(names, ", "),
+			jsString(importPath(module, from, c.moduleLayout.ImportExtension))))
+	}
+	return imports
+}
+
+// gets the path that the module from imports the module to with, relative to
+// the directory of from.
+func importPath(from, to, extension string) string {
+	fromDir := strings.Split(from, "/")
+	fromDir = fromDir[:len(fromDir)-1]
+	toParts := strings.Split(to, "/")
+
+	i := 0
+	for i < len(fromDir) && i < len(toParts)-1 && fromDir[i] == toParts[i] {
+		i++
+	}
+
+	parts := []string{}
+	for range fromDir[i:] {
+		parts = append(parts, "..")
+	}
+	parts = append(parts, toParts[i:]...)
+
+	path := strings.Join(parts, "/")
+	if !strings.HasPrefix(path, "../") {
+		path = "./" + path
+	}
+	return path + extension
+}
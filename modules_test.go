@@ -0,0 +1,96 @@
+package supervillain
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Southclaws/supervillain/internal/fixtures/people"
+	"github.com/Southclaws/supervillain/internal/fixtures/shop/orders"
+)
+
+func TestConvertModules(t *testing.T) {
+	c := NewConverter(nil, WithModuleLayout(ModuleLayout{
+		Root: "github.com/Southclaws/supervillain/internal/fixtures",
+	}))
+
+	assert.Equal(t, map[string]string{
+		"people.ts": `import { z } from "zod"
+
+export const PersonSchema = z.object({
+  Name: z.string(),
+})
+export type Person = z.infer<typeof PersonSchema>
+
+`,
+		"shop/orders.ts": `import { z } from "zod"
+import { type Person, PersonSchema } from "../people"
+
+export const LineSchema = z.object({
+  Product: z.string(),
+})
+export type Line = z.infer<typeof LineSchema>
+
+export type Order = {
+  Buyer: Person
+  Lines: Line[] | null
+  Parent: Order | null
+}
+export const OrderSchema: z.ZodType<Order> = z.object({
+  Buyer: PersonSchema,
+  Lines: LineSchema.array().nullable(),
+  Parent: z.lazy(() => OrderSchema).nullable(),
+})
+
+`,
+	}, c.ConvertModules([]interface{}{orders.Order{}}))
+}
+
+func TestConvertModulesIndex(t *testing.T) {
+	c := NewConverter(nil, WithModuleLayout(ModuleLayout{
+		Group: func(t reflect.Type) string {
+			if t.PkgPath() == "github.com/Southclaws/supervillain/internal/fixtures/people" {
+				return "models/people"
+			}
+			return "models/api/" + t.Name()
+		},
+		Index:           true,
+		ImportExtension: ".js",
+	}))
+
+	modules := c.ConvertModules([]interface{}{orders.Line{}, people.Person{}, Account{}})
+	assert.Equal(t, `export * from "./models/api/Account.js"
+export * from "./models/api/Line.js"
+export * from "./models/people.js"
+`, modules["index.ts"])
+	assert.Len(t, modules, 4)
+}
+
+func TestConvertModulesE(t *testing.T) {
+	c := NewConverter(nil)
+	_, err := c.ConvertModulesE([]interface{}{struct{ C chan int }{}})
+	assert.Error(t, err)
+
+	c = NewConverter(nil)
+	modules, err := c.ConvertModulesE([]interface{}{people.Person{}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"github.com/Southclaws/supervillain/internal/fixtures/people.ts": `import { z } from "zod"
+
+export const PersonSchema = z.object({
+  Name: z.string(),
+})
+export type Person = z.infer<typeof PersonSchema>
+
+`,
+	}, modules)
+}
+
+func TestImportPath(t *testing.T) {
+	assert.Equal(t, "./users", importPath("orders", "users", ""))
+	assert.Equal(t, "../users.js", importPath("shop/orders", "users", ".js"))
+	assert.Equal(t, "./invoices", importPath("billing/accounts", "billing/invoices", ""))
+	assert.Equal(t, "./billing/invoices", importPath("orders", "billing/invoices", ""))
+	assert.Equal(t, "../../a/b", importPath("x/y/z", "a/b", ""))
+}
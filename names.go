@@ -0,0 +1,167 @@
+package supervillain
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ErrNameCollision is reported when two different types would be given the
+// same schema name.
+const ErrNameCollision ErrorCode = "name_collision"
+
+// NameCollisionPolicy decides what happens when two different types, usually
+// from different packages, would be given the same schema name.
+type NameCollisionPolicy int
+
+const (
+	// NameCollisionError reports the collision as an error.
+	NameCollisionError NameCollisionPolicy = iota
+	// NameCollisionPrefixPackage prefixes the names of the types that collide
+	// with their package names, so `auth.Account` becomes `AuthAccountSchema`
+	// and `billing.Account` becomes `BillingAccountSchema`.
+	NameCollisionPrefixPackage
+)
+
+type nameCollisionOption func(t reflect.Type, name string) (string, bool)
+
+func (n nameCollisionOption) apply(c *Converter) {
+	c.collision = n
+}
+
+// WithNameCollisionPolicy sets how colliding type names are handled. By default
+// collisions are reported as errors.
+func WithNameCollisionPolicy(p NameCollisionPolicy) Option {
+	switch p {
+	case NameCollisionPrefixPackage:
+		return nameCollisionOption(func(t reflect.Type, name string) (string, bool) {
+			return packageName(t.PkgPath()) + name, true
+		})
+	default:
+		return nameCollisionOption(nil)
+	}
+}
+
+// WithNameCollisionFunc resolves colliding type names by calling fn with each
+// of the types that collide and the name they would have been given. The names
+// returned by fn are used instead, they must not collide with any other name.
+func WithNameCollisionFunc(fn func(t reflect.Type, name string) string) Option {
+	return nameCollisionOption(func(t reflect.Type, name string) (string, bool) {
+		return fn(t, name), true
+	})
+}
+
+// identifies a named type by its full package path and name. unlike the name
+// alone, this is unique across packages.
+func typeKey(t reflect.Type) string {
+	return fmt.Sprintf("%s.%s", t.PkgPath(), t.Name())
+}
+
+// gets the name used for the schema of a named type. the first type to claim a
+// name gets to keep it, any other type with the same name is resolved with the
+// collision policy.
+func (c *Converter) nameFor(t reflect.Type) string {
+	key := typeKey(t)
+	if _, ok := c.names[key]; ok && c.types[key] != t {
+		// types declared inside functions share a key with any other type of
+		// the same name in the same package.
+		c.fail(t, ErrNameCollision, fmt.Sprintf("%s is declared more than once in %s", t.Name(), t.PkgPath()))
+	}
+
+	if isGeneric(t) {
+		return c.claimName(key, c.instanceName(t), t)
+	}
+
+	return c.claimName(key, t.Name(), t)
+}
+
+// gets the name for a key, claiming the given name if the key does not have one
+// yet. t is the type that the key belongs to, for reporting collisions.
+//
+// which of two colliding types is found first depends on the order of the
+// inputs and fields, so when collisions are resolved neither keeps the name.
+// the type that claimed it first is renamed too, and the name is kept back so
+// that any other type that collides with it later is renamed as well.
+func (c *Converter) claimName(key, name string, t reflect.Type) string {
+	if name, ok := c.names[key]; ok {
+		return name
+	}
+
+	if c.names == nil {
+		c.names = make(map[string]string)
+		c.owners = make(map[string]string)
+		c.types = make(map[string]reflect.Type)
+	}
+
+	if owner, ok := c.owners[name]; ok {
+		if c.collision == nil {
+			c.fail(t, ErrNameCollision, fmt.Sprintf("%s and %s would both be named %s", owner, key, name))
+		} else {
+			if owner != "" {
+				c.owners[name] = ""
+				c.names[owner] = c.resolveCollision(owner, name, c.types[owner])
+				c.owners[c.names[owner]] = owner
+			}
+			name = c.resolveCollision(key, name, t)
+		}
+	}
+
+	c.names[key] = name
+	c.owners[name] = key
+	c.types[key] = t
+
+	return name
+}
+
+// renames a type whose name collides with another using the collision policy.
+// the new name must not collide with anything either.
+func (c *Converter) resolveCollision(key, name string, t reflect.Type) string {
+	resolved, ok := c.collision(t, name)
+	if owner, taken := c.owners[resolved]; !ok || taken {
+		if !taken {
+			owner = c.owners[name]
+		}
+		c.fail(t, ErrNameCollision, fmt.Sprintf("%s and %s would both be named %s", owner, key, resolved))
+		return name
+	}
+	return resolved
+}
+
+var matchMajorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// guesses the package name from an import path, ignoring major version suffixes
+// and capitalising the result so it can be used as part of an identifier.
+func packageName(path string) string {
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	if matchMajorVersion.MatchString(name) && len(parts) > 1 {
+		name = parts[len(parts)-2]
+	}
+	if i := strings.Index(name, ".v"); i > 0 {
+		name = name[:i]
+	}
+
+	return pascalCase(name)
+}
+
+// capitalises each word of a string and drops anything that is not a letter or
+// digit, for example `go-json` becomes `GoJson`.
+func pascalCase(s string) string {
+	output := strings.Builder{}
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		output.WriteRune(r)
+	}
+
+	return output.String()
+}
@@ -0,0 +1,146 @@
+package supervillain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Southclaws/supervillain/internal/fixtures/auth"
+	"github.com/Southclaws/supervillain/internal/fixtures/billing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Customer struct {
+	Billing billing.Account
+	Auth    auth.Account
+}
+
+func TestNameCollisionError(t *testing.T) {
+	c := NewConverter(map[string]CustomFn{})
+	_, err := c.ConvertE(Customer{})
+
+	var errs ConversionErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "Customer.Auth", errs[0].Path)
+	assert.Equal(t, ErrNameCollision, errs[0].Code)
+	assert.Equal(t, reflect.TypeOf(auth.Account{}), errs[0].Type)
+
+	assert.Panics(t, func() {
+		StructToZodSchema(Customer{})
+	})
+}
+
+func TestNameCollisionPrefixPackage(t *testing.T) {
+	c := NewConverter(map[string]CustomFn{}, WithNameCollisionPolicy(NameCollisionPrefixPackage))
+	assert.Equal(t,
+		`export const AuthAccountSchema = z.object({
+  Email: z.string(),
+})
+export type AuthAccount = z.infer<typeof AuthAccountSchema>
+
+export const BillingAccountSchema = z.object({
+  Balance: z.number().int(),
+})
+export type BillingAccount = z.infer<typeof BillingAccountSchema>
+
+export const CustomerSchema = z.object({
+  Billing: BillingAccountSchema,
+  Auth: AuthAccountSchema,
+})
+export type Customer = z.infer<typeof CustomerSchema>
+
+`, c.Convert(Customer{}))
+}
+
+func TestNameCollisionPrefixPackageOrder(t *testing.T) {
+	expected := `export const AuthAccountSchema = z.object({
+  Email: z.string(),
+})
+export type AuthAccount = z.infer<typeof AuthAccountSchema>
+
+export const BillingAccountSchema = z.object({
+  Balance: z.number().int(),
+})
+export type BillingAccount = z.infer<typeof BillingAccountSchema>
+
+`
+
+	c := NewConverter(map[string]CustomFn{}, WithNameCollisionPolicy(NameCollisionPrefixPackage))
+	assert.Equal(t, expected, c.ConvertSlice([]interface{}{billing.Account{}, auth.Account{}}))
+
+	c = NewConverter(map[string]CustomFn{}, WithNameCollisionPolicy(NameCollisionPrefixPackage))
+	assert.Equal(t, expected, c.ConvertSlice([]interface{}{auth.Account{}, billing.Account{}}))
+}
+
+func TestNameCollisionFunc(t *testing.T) {
+	c := NewConverter(map[string]CustomFn{}, WithNameCollisionFunc(func(t reflect.Type, name string) string {
+		return name + "From" + packageName(t.PkgPath())
+	}))
+	assert.Equal(t,
+		`export const AccountFromAuthSchema = z.object({
+  Email: z.string(),
+})
+export type AccountFromAuth = z.infer<typeof AccountFromAuthSchema>
+
+export const AccountFromBillingSchema = z.object({
+  Balance: z.number().int(),
+})
+export type AccountFromBilling = z.infer<typeof AccountFromBillingSchema>
+
+export const CustomerSchema = z.object({
+  Billing: AccountFromBillingSchema,
+  Auth: AccountFromAuthSchema,
+})
+export type Customer = z.infer<typeof CustomerSchema>
+
+`, c.Convert(Customer{}))
+}
+
+func TestNameCollisionFuncStillCollides(t *testing.T) {
+	c := NewConverter(map[string]CustomFn{}, WithNameCollisionFunc(func(t reflect.Type, name string) string {
+		return "Customer"
+	}))
+	_, err := c.ConvertE(Customer{})
+
+	var errs ConversionErrors
+	require.True(t, errors.As(err, &errs))
+	assert.Equal(t, ErrNameCollision, errs[0].Code)
+}
+
+func TestNameCollisionSamePackage(t *testing.T) {
+	type Account struct {
+		ID string
+	}
+	type Wrapper struct {
+		Account Account
+	}
+
+	c := NewConverter(map[string]CustomFn{}, WithNameCollisionPolicy(NameCollisionPrefixPackage))
+	c.Convert(Wrapper{})
+
+	func() {
+		type Account struct {
+			Name string
+		}
+		type Other struct {
+			Account Account
+		}
+
+		_, err := c.ConvertE(Other{})
+
+		var errs ConversionErrors
+		require.True(t, errors.As(err, &errs))
+		assert.Equal(t, "Other.Account", errs[0].Path)
+		assert.Equal(t, ErrNameCollision, errs[0].Code)
+	}()
+}
+
+func TestPackageName(t *testing.T) {
+	assert.Equal(t, "Billing", packageName("github.com/acme/billing"))
+	assert.Equal(t, "Chi", packageName("github.com/go-chi/chi/v5"))
+	assert.Equal(t, "Yaml", packageName("gopkg.in/yaml.v3"))
+	assert.Equal(t, "GoJson", packageName("github.com/goccy/go-json"))
+	assert.Equal(t, "Supervillain", packageName("github.com/Southclaws/supervillain"))
+}
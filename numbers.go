@@ -0,0 +1,99 @@
+package supervillain
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Int64Mode decides how 64 bit integers are converted. JavaScript numbers can
+// only represent integers up to 2^53 exactly, so larger values may be rounded.
+type Int64Mode int
+
+const (
+	// Int64Number converts 64 bit integers to numbers, which is unsafe for
+	// values beyond 2^53 but matches what JSON.parse produces.
+	Int64Number Int64Mode = iota
+	// Int64BigInt converts 64 bit integers to bigints with the full Go range,
+	// this requires the JSON to be parsed with bigint support.
+	Int64BigInt
+	// Int64String converts 64 bit integers to strings of digits, for use with
+	// fields that are encoded as strings.
+	Int64String
+)
+
+type int64ModeOption Int64Mode
+
+func (i int64ModeOption) apply(c *Converter) {
+	c.int64Mode = Int64Mode(i)
+}
+
+// WithInt64Mode sets how 64 bit integers are converted, the default is
+// Int64Number.
+func WithInt64Mode(m Int64Mode) Option {
+	return int64ModeOption(m)
+}
+
+func isInteger(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+func isUnsigned(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+// converts an integer type to a schema that only accepts whole numbers within
+// the range of the Go type. when coerce is set, the schema accepts strings and
+// converts them, as used for the `,string` option.
+func (c *Converter) convertInteger(t reflect.Type, coerce bool) *Primitive {
+	bits := t.Bits()
+	if bits < 64 {
+		if isUnsigned(t) {
+			return &Primitive{Kind: PrimitiveNumber, Coerce: coerce, Checks: []Check{
+				{CheckInt, ""},
+				{CheckMin, "0"},
+				{CheckMax, fmt.Sprint(uint64(1)<<bits - 1)},
+			}}
+		}
+		return &Primitive{Kind: PrimitiveNumber, Coerce: coerce, Checks: []Check{
+			{CheckInt, ""},
+			{CheckMin, fmt.Sprint(-(int64(1) << (bits - 1)))},
+			{CheckMax, fmt.Sprint(int64(1)<<(bits-1) - 1)},
+		}}
+	}
+
+	switch c.int64Mode {
+	case Int64BigInt:
+		if isUnsigned(t) {
+			return &Primitive{Kind: PrimitiveBigInt, Coerce: coerce, Checks: []Check{
+				{CheckMin, "0"},
+				{CheckMax, fmt.Sprint(uint64(1<<64 - 1))},
+			}}
+		}
+		return &Primitive{Kind: PrimitiveBigInt, Coerce: coerce, Checks: []Check{
+			{CheckMin, fmt.Sprint(int64(-1 << 63))},
+			{CheckMax, fmt.Sprint(int64(1<<63 - 1))},
+		}}
+
+	case Int64String:
+		// with the `,string` option the value is already a string, so there is
+		// nothing to coerce and the schema is the same.
+		if isUnsigned(t) {
+			return &Primitive{Kind: PrimitiveString, Checks: []Check{{CheckRegex, `^\d+$`}}}
+		}
+		return &Primitive{Kind: PrimitiveString, Checks: []Check{{CheckRegex, `^-?\d+$`}}}
+	}
+
+	if isUnsigned(t) {
+		return &Primitive{Kind: PrimitiveNumber, Coerce: coerce, Checks: []Check{{CheckInt, ""}, {CheckNonNegative, ""}}}
+	}
+	return &Primitive{Kind: PrimitiveNumber, Coerce: coerce, Checks: []Check{{CheckInt, ""}}}
+}
@@ -0,0 +1,132 @@
+package supervillain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Sizes struct {
+	I8  int8
+	I16 int16
+	I32 int32
+	I64 int64
+	U8  uint8
+	U16 uint16
+	U32 uint32
+	U64 uint64
+	F32 float32
+	F64 float64
+}
+
+func TestIntegerBounds(t *testing.T) {
+	assert.Equal(t,
+		`export const SizesSchema = z.object({
+  I8: z.number().int().min(-128).max(127),
+  I16: z.number().int().min(-32768).max(32767),
+  I32: z.number().int().min(-2147483648).max(2147483647),
+  I64: z.number().int(),
+  U8: z.number().int().min(0).max(255),
+  U16: z.number().int().min(0).max(65535),
+  U32: z.number().int().min(0).max(4294967295),
+  U64: z.number().int().nonnegative(),
+  F32: z.number(),
+  F64: z.number(),
+})
+export type Sizes = z.infer<typeof SizesSchema>
+
+`,
+		StructToZodSchema(Sizes{}))
+}
+
+func TestInt64BigInt(t *testing.T) {
+	type Ledger struct {
+		Balance int64
+		Total   uint64
+		Count   uint8
+		Entries []int64
+	}
+	assert.Equal(t,
+		`export const LedgerSchema = z.object({
+  Balance: z.bigint().min(-9223372036854775808n).max(9223372036854775807n),
+  Total: z.bigint().min(0n).max(18446744073709551615n),
+  Count: z.number().int().min(0).max(255),
+  Entries: z.bigint().min(-9223372036854775808n).max(9223372036854775807n).array().nullable(),
+})
+export type Ledger = z.infer<typeof LedgerSchema>
+
+`,
+		StructToZodSchema(Ledger{}, WithInt64Mode(Int64BigInt)))
+}
+
+func TestInt64String(t *testing.T) {
+	type Ledger struct {
+		Balance int64
+		Total   uint64
+		Count   int32
+	}
+	assert.Equal(t,
+		`export const LedgerSchema = z.object({
+  Balance: z.string().regex(/^-?\d+$/),
+  Total: z.string().regex(/^\d+$/),
+  Count: z.number().int().min(-2147483648).max(2147483647),
+})
+export type Ledger = z.infer<typeof LedgerSchema>
+
+`,
+		StructToZodSchema(Ledger{}, WithInt64Mode(Int64String)))
+}
+
+func TestInt64BigIntQuoted(t *testing.T) {
+	type Post struct {
+		ID    int64 `json:"id,string"`
+		Views int64 `json:"views"`
+	}
+	c := NewConverter(nil, WithInt64Mode(Int64BigInt), WithQuotedMode(QuotedCoerce))
+	assert.Equal(t,
+		`export const PostSchema = z.object({
+  id: z.coerce.bigint().min(-9223372036854775808n).max(9223372036854775807n),
+  views: z.bigint().min(-9223372036854775808n).max(9223372036854775807n),
+})
+export type Post = z.infer<typeof PostSchema>
+
+`,
+		c.Convert(Post{}))
+}
+
+func TestInt64StringQuoted(t *testing.T) {
+	type Post struct {
+		ID    int64  `json:"id,string"`
+		Views uint64 `json:"views,string"`
+	}
+	c := NewConverter(nil, WithInt64Mode(Int64String), WithQuotedMode(QuotedCoerce))
+	assert.Equal(t,
+		`export const PostSchema = z.object({
+  id: z.string().regex(/^-?\d+$/),
+  views: z.string().regex(/^\d+$/),
+})
+export type Post = z.infer<typeof PostSchema>
+
+`,
+		c.Convert(Post{}))
+}
+
+type Account struct {
+	Balance int64
+	Parent  *Account
+}
+
+func TestInt64BigIntRecursive(t *testing.T) {
+	assert.Equal(t,
+		`export type Account = {
+  Balance: bigint
+  Parent: Account | null
+}
+export const AccountSchema: z.ZodType<Account> = z.object({
+  Balance: z.bigint().min(-9223372036854775808n).max(9223372036854775807n),
+  Parent: z.lazy(() => AccountSchema).nullable(),
+})
+
+`,
+		StructToZodSchema(Account{}, WithInt64Mode(Int64BigInt)))
+}
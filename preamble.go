@@ -0,0 +1,129 @@
+package supervillain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrUnsupportedImport is reported when zod is imported from an entry point
+// that the schemas cannot be used with, such as ZodPrinter given to
+// ConvertLibrary with an import of zod/mini.
+const ErrUnsupportedImport ErrorCode = "unsupported_import"
+
+// Preamble configures what is written before the schemas: the import of zod,
+// a banner and directives for tools that should leave the output alone.
+type Preamble struct {
+	// Import is the module zod is imported from, defaults to "zod". Use
+	// "zod/v4" for that entry point. With "zod/mini" the schemas are printed
+	// by ZodMiniPrinter, as it has functions in place of the methods of Zod.
+	Import string
+	// Namespace imports zod with `import * as z` instead of `import { z }`.
+	Namespace bool
+	// Banner adds a "Code generated by supervillain; DO NOT EDIT." comment
+	// that lists the Go types the output was generated from.
+	Banner bool
+	// ESLintDisable adds an `eslint-disable` comment so linters skip the
+	// output.
+	ESLintDisable bool
+	// TSNoCheck adds a `@ts-nocheck` comment so TypeScript does not check the
+	// output.
+	TSNoCheck bool
+}
+
+type preambleOption Preamble
+
+func (p preambleOption) apply(c *Converter) {
+	preamble := Preamble(p)
+	if preamble.Import == "" {
+		preamble.Import = "zod"
+	}
+	c.preamble = &preamble
+}
+
+// WithPreamble writes an import of zod, and optionally a banner and directives,
+// before the schemas. Without it Convert writes the schemas alone and
+// ConvertModules only imports zod from "zod".
+func WithPreamble(p Preamble) Option {
+	return preambleOption(p)
+}
+
+// the preamble used by ConvertModules when none is given, as modules must
+// always import zod.
+var defaultPreamble = Preamble{Import: "zod"}
+
+// builds the banner, listing the given Go types, and the directives, ending with
+// a blank line if there are any.
+func (p Preamble) header(sources []string) string {
+	parts := []string{}
+	if p.Banner {
+		banner := "// Code generated by supervillain; DO NOT EDIT.\n"
+		if len(sources) > 0 {
+			banner += "//\n// Generated from:\n"
+			for _, source := range sources {
+				banner += fmt.Sprintf("//   %s\n", source)
+			}
+		}
+		parts = append(parts, banner)
+	}
+
+	directives := ""
+	if p.ESLintDisable {
+		directives += "/* eslint-disable */\n"
+	}
+	if p.TSNoCheck {
+		directives += "// @ts-nocheck\n"
+	}
+	if directives != "" {
+		parts = append(parts, directives)
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, "\n") + "\n"
+}
+
+// the statement that imports zod.
+func (p Preamble) importZod() string {
+	if p.Namespace {
+		return fmt.Sprintf("import * as z from %s\n", jsString(p.Import))
+	}
+	return fmt.Sprintf("import { z } from %s\n", jsString(p.Import))
+}
+
+// checks whether zod is imported from one of the entry points of zod/mini.
+func (p Preamble) isZodMini() bool {
+	switch p.Import {
+	case "zod/mini", "zod/v4-mini", "zod/v4/mini":
+		return true
+	}
+	return false
+}
+
+// reports ZodPrinter being used with an import of zod/mini, which has functions
+// such as `z.optional(s)` in place of the methods that it prints.
+func (c *Converter) checkImport(l Library, p Preamble) {
+	if _, ok := l.(ZodPrinter); ok && p.isZodMini() {
+		c.fail(nil, ErrUnsupportedImport, fmt.Sprintf("zod cannot be imported from %s as it does not have the methods that ZodPrinter prints, use ZodMiniPrinter", p.Import))
+	}
+}
+
+// gets the preamble to use, which for modules falls back to the default.
+func (c *Converter) preambleOrDefault() Preamble {
+	if c.preamble != nil {
+		return *c.preamble
+	}
+	return defaultPreamble
+}
+
+// lists the Go types of a set of schemas by their full package path and name,
+// for the banner.
+func sources(outputs map[string]entry) []string {
+	keys := make([]string, 0, len(outputs))
+	for key := range outputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
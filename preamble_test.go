@@ -0,0 +1,120 @@
+package supervillain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Southclaws/supervillain/internal/fixtures/people"
+	"github.com/Southclaws/supervillain/internal/fixtures/shop/orders"
+)
+
+func TestPreamble(t *testing.T) {
+	c := NewConverter(nil, WithPreamble(Preamble{}))
+	assert.Equal(t,
+		`import { z } from "zod"
+
+export const PersonSchema = z.object({
+  Name: z.string(),
+})
+export type Person = z.infer<typeof PersonSchema>
+
+`,
+		c.Convert(people.Person{}))
+}
+
+func TestPreambleEverything(t *testing.T) {
+	c := NewConverter(nil, WithPreamble(Preamble{
+		Import:        "zod/v4",
+		Namespace:     true,
+		Banner:        true,
+		ESLintDisable: true,
+		TSNoCheck:     true,
+	}))
+	assert.Equal(t,
+		`// Code generated by supervillain; DO NOT EDIT.
+//
+// Generated from:
+//   github.com/Southclaws/supervillain/internal/fixtures/people.Person
+//   github.com/Southclaws/supervillain/internal/fixtures/shop/orders.Line
+
+/* eslint-disable */
+// @ts-nocheck
+
+import * as z from "zod/v4"
+
+export const LineSchema = z.object({
+  Product: z.string(),
+})
+export type Line = z.infer<typeof LineSchema>
+
+export const PersonSchema = z.object({
+  Name: z.string(),
+})
+export type Person = z.infer<typeof PersonSchema>
+
+`,
+		c.ConvertSlice([]interface{}{people.Person{}, orders.Line{}}))
+}
+
+func TestPreambleModules(t *testing.T) {
+	c := NewConverter(nil,
+		WithPreamble(Preamble{Import: "zod/v4", Banner: true}),
+		WithModuleLayout(ModuleLayout{
+			Root:  "github.com/Southclaws/supervillain/internal/fixtures",
+			Index: true,
+		}))
+
+	modules := c.ConvertModules([]interface{}{people.Person{}})
+	assert.Equal(t, map[string]string{
+		"people.ts": `// Code generated by supervillain; DO NOT EDIT.
+//
+// Generated from:
+//   github.com/Southclaws/supervillain/internal/fixtures/people.Person
+
+import { z } from "zod/v4"
+
+export const PersonSchema = z.object({
+  Name: z.string(),
+})
+export type Person = z.infer<typeof PersonSchema>
+
+`,
+		"index.ts": `// Code generated by supervillain; DO NOT EDIT.
+
+export * from "./people"
+`,
+	}, modules)
+}
+
+func TestPreambleZodMini(t *testing.T) {
+	for _, module := range []string{"zod/mini", "zod/v4-mini", "zod/v4/mini"} {
+		c := NewConverter(nil, WithPreamble(Preamble{Import: module, Namespace: true}))
+		assert.Equal(t,
+			`import * as z from "`+module+`"
+
+export const PersonSchema = z.object({
+  Name: z.string(),
+})
+export type Person = z.infer<typeof PersonSchema>
+
+`,
+			c.Convert(people.Person{}))
+	}
+
+	// ZodPrinter prints methods that zod/mini does not have.
+	c := NewConverter(nil, WithPreamble(Preamble{Import: "zod/mini"}))
+	_, err := c.ConvertLibraryE(ZodPrinter{}, []interface{}{people.Person{}})
+
+	var errs ConversionErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 1)
+	assert.Equal(t, ErrUnsupportedImport, errs[0].Code)
+
+	// other libraries do not import zod.
+	c = NewConverter(nil, WithPreamble(Preamble{Import: "zod/mini"}))
+	_, err = c.ConvertLibraryE(ValibotPrinter{}, []interface{}{people.Person{}})
+	assert.NoError(t, err)
+}
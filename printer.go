@@ -0,0 +1,296 @@
+package supervillain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ZodPrinter prints declarations as Zod schemas and the TypeScript types that
+// are inferred from them. This is what Convert and friends write out.
+type ZodPrinter struct {
+	// Prefix is added to the start of every declared name.
+	Prefix string
+}
+
+// Declaration prints the schema and type of a declaration, along with the
+// object of values of an enum.
+func (p ZodPrinter) Declaration(d *Declaration) string {
+	name := p.Prefix + d.Name
+	schemaName := schemaName(p.Prefix, d.Name)
+	jsdoc, _ := formatDoc(d.Doc, 0)
+
+	schema := d.Schema
+	if u, ok := schema.(*Union); ok && d.Recursive {
+		// members that refer to themselves are annotated with z.ZodType, which
+		// z.discriminatedUnion does not accept, so they fall back to z.union.
+		plain := *u
+		plain.Discriminator = ""
+		schema = &plain
+	}
+	zod := p.Schema(schema, 0) + describeCall(d.Description)
+
+	output := strings.Builder{}
+	switch {
+	case len(d.Params) > 0:
+		typeParams := make([]string, len(d.Params))
+		valueParams := make([]string, len(d.Params))
+		for i, param := range d.Params {
+			typeParams[i] = fmt.Sprintf("%s extends z.ZodTypeAny", param)
+			valueParams[i] = fmt.Sprintf("%s: %s", strings.ToLower(param), param)
+		}
+		output.WriteString(fmt.Sprintf("export const %s = <%s>(%s) => %s\n",
+			schemaName, strings.Join(typeParams, ", "), strings.Join(valueParams, ", "), zod))
+		output.WriteString(jsdoc)
+		output.WriteString(fmt.Sprintf("export type %s<%s> = z.infer<ReturnType<typeof %s<%s>>>",
+			name, strings.Join(typeParams, ", "), schemaName, strings.Join(d.Params, ", ")))
+
+	case d.Recursive:
+		// z.infer cannot be used for types that refer to themselves so the type
+		// is written out by hand and the schema is annotated with it.
+		output.WriteString(jsdoc)
+		output.WriteString(fmt.Sprintf("export type %s = %s\n", name, p.Type(d.Schema, 0)))
+		output.WriteString(fmt.Sprintf("export const %s: z.ZodType<%s> = %s", schemaName, name, zod))
+
+	default:
+		output.WriteString(fmt.Sprintf("export const %s = %s\n", schemaName, zod))
+		output.WriteString(jsdoc)
+		output.WriteString(fmt.Sprintf("export type %s = z.infer<typeof %s>", name, schemaName))
+	}
+
+	output.WriteString(enumObject(name, d.Schema))
+
+	return output.String()
+}
+
+// Name is "zod".
+func (p ZodPrinter) Name() string {
+	return "zod"
+}
+
+// Import imports zod as the preamble says to.
+func (p ZodPrinter) Import(preamble Preamble) string {
+	return preamble.importZod()
+}
+
+// declares the object of values of an enum, if they are named.
+func enumObject(name string, s Schema) string {
+	e, ok := s.(*Enum)
+	if !ok || len(e.Constants) == 0 {
+		return ""
+	}
+
+	output := strings.Builder{}
+	output.WriteString(fmt.Sprintf("\nexport const %s = {\n", name))
+	for _, constant := range e.Constants {
+		output.WriteString(fmt.Sprintf("%s%s: %s,\n", indentation(1), constant.Name, constant.Value))
+	}
+	output.WriteString("} as const")
+	return output.String()
+}
+
+// Schema prints a schema as a Zod expression. Objects are indented by the
+// given level, as they would be when written inside other objects at it.
+func (p ZodPrinter) Schema(s Schema, indent int) string {
+	switch s := s.(type) {
+	case *Primitive:
+		return p.primitive(s)
+
+	case *Const:
+		if s.Value == "null" {
+			return "z.null()"
+		}
+		return fmt.Sprintf("z.literal(%s)", s.Value)
+
+	case *Enum:
+		return enumSchema(s.Values)
+
+	case *Object:
+		output := strings.Builder{}
+		output.WriteString("z.object({\n")
+		for _, f := range s.Fields {
+			output.WriteString(p.field(f, indent+1))
+		}
+		output.WriteString(indentation(indent))
+		output.WriteString("})")
+		return output.String()
+
+	case *Array:
+		return fmt.Sprintf("%s.array()%s", p.Schema(s.Items, indent), p.checks(s.Checks, false))
+
+	case *Tuple:
+		items := make([]string, len(s.Items))
+		for i, item := range s.Items {
+			items[i] = p.Schema(item, indent)
+		}
+		return fmt.Sprintf("z.tuple([%s])", strings.Join(items, ", "))
+
+	case *Record:
+		return fmt.Sprintf("z.record(%s, %s)", p.Schema(s.Key, indent), p.Schema(s.Value, indent))
+
+	case *Union:
+		members := make([]string, len(s.Members))
+		for i, m := range s.Members {
+			members[i] = p.Schema(m, indent)
+		}
+		switch {
+		case s.Discriminator != "":
+			return fmt.Sprintf("z.discriminatedUnion(%s, [%s])", jsString(s.Discriminator), strings.Join(members, ", "))
+		case len(members) == 1:
+			return members[0]
+		}
+		return fmt.Sprintf("z.union([%s])", strings.Join(members, ", "))
+
+	case *OrEmpty:
+		return fmt.Sprintf("%s.or(%s)", p.Schema(s.Schema, indent), p.Schema(&Const{s.Value}, indent))
+
+	case *Reference:
+		name := schemaName(p.Prefix, s.Name)
+		if s.Lazy {
+			return fmt.Sprintf("z.lazy(() => %s)", name)
+		}
+		if len(s.Args) > 0 {
+			args := make([]string, len(s.Args))
+			for i, arg := range s.Args {
+				args[i] = p.Schema(arg, indent)
+			}
+			return fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+		}
+		return name
+
+	case *Param:
+		return strings.ToLower(s.Name)
+
+	case *Custom:
+		return s.Zod
+	}
+
+	return "z.unknown()"
+}
+
+func (p ZodPrinter) field(f *Field, indent int) string {
+	jsdoc, _ := formatDoc(f.Doc, indent)
+
+	modifiers := ""
+	switch {
+	case f.Nullish:
+		modifiers = ".nullish()"
+	case f.Optional && f.Nullable:
+		modifiers = ".optional().nullable()"
+	case f.Optional:
+		modifiers = ".optional()"
+	case f.Nullable:
+		modifiers = ".nullable()"
+	}
+	if f.Default != "" {
+		modifiers += fmt.Sprintf(".default(%s)", f.Default)
+	}
+	modifiers += describeCall(f.Description)
+
+	return fmt.Sprintf("%s%s%s: %s%s%s,\n", jsdoc, indentation(indent), f.Name, p.Schema(f.Schema, indent), f.Methods, modifiers)
+}
+
+func (p ZodPrinter) primitive(s *Primitive) string {
+	schema := ""
+	switch s.Kind {
+	case PrimitiveString:
+		schema = "z.string()"
+	case PrimitiveNumber:
+		schema = "z.number()"
+	case PrimitiveBoolean:
+		if s.Coerce {
+			// z.coerce.boolean() would accept "false" as true, like Boolean().
+			schema = `z.enum(["true", "false"]).transform((v) => v === "true")`
+		} else {
+			schema = "z.boolean()"
+		}
+	case PrimitiveBigInt:
+		schema = "z.bigint()"
+	case PrimitiveAny:
+		schema = "z.any()"
+	default:
+		schema = "z.unknown()"
+	}
+	if s.Coerce && s.Kind != PrimitiveBoolean {
+		schema = strings.Replace(schema, "z.", "z.coerce.", 1)
+	}
+
+	return schema + p.checks(s.Checks, s.Kind == PrimitiveBigInt)
+}
+
+// the Zod methods for each kind of check, with the value of the check.
+var zodChecks = map[CheckKind]string{
+	CheckInt:                ".int()",
+	CheckNonNegative:        ".nonnegative()",
+	CheckMin:                ".min(%s)",
+	CheckMax:                ".max(%s)",
+	CheckLength:             ".length(%s)",
+	CheckGreaterThan:        ".gt(%s)",
+	CheckGreaterThanOrEqual: ".gte(%s)",
+	CheckLessThan:           ".lt(%s)",
+	CheckLessThanOrEqual:    ".lte(%s)",
+	CheckNonZero:            ".refine((v) => v !== 0)",
+	CheckTrue:               ".refine((v) => v)",
+	CheckEmail:              ".email()",
+	CheckURL:                ".url()",
+	CheckUUID:               ".uuid()",
+	CheckRegex:              ".regex(/%s/)",
+	CheckLowercase:          ".refine((v) => v === v.toLowerCase())",
+	CheckUppercase:          ".refine((v) => v === v.toUpperCase())",
+}
+
+func (p ZodPrinter) checks(checks []Check, bigint bool) string {
+	output := strings.Builder{}
+	// a refinement returns a ZodEffects, which has none of the methods of the
+	// schema it refines, so refinements come after every other check.
+	refinements := strings.Builder{}
+	for _, check := range checks {
+		switch check.Kind {
+		case CheckNonZero, CheckTrue, CheckLowercase, CheckUppercase:
+			if check.Kind == CheckNonZero && bigint {
+				refinements.WriteString(".refine((v) => v !== 0n)")
+			} else {
+				refinements.WriteString(zodChecks[check.Kind])
+			}
+		case CheckIP:
+			if check.Value == "" {
+				output.WriteString(".ip()")
+			} else {
+				output.WriteString(fmt.Sprintf(".ip({ version: %s })", jsString(check.Value)))
+			}
+		case CheckStartsWith:
+			output.WriteString(fmt.Sprintf(".startsWith(%s)", jsString(check.Value)))
+		case CheckEndsWith:
+			output.WriteString(fmt.Sprintf(".endsWith(%s)", jsString(check.Value)))
+		case CheckIncludes:
+			output.WriteString(fmt.Sprintf(".includes(%s)", jsString(check.Value)))
+		case CheckMin, CheckMax, CheckGreaterThan, CheckGreaterThanOrEqual, CheckLessThan, CheckLessThanOrEqual:
+			value := check.Value
+			if bigint {
+				value += "n"
+			}
+			output.WriteString(fmt.Sprintf(zodChecks[check.Kind], value))
+		default:
+			if strings.Contains(zodChecks[check.Kind], "%s") {
+				output.WriteString(fmt.Sprintf(zodChecks[check.Kind], check.Value))
+			} else {
+				output.WriteString(zodChecks[check.Kind])
+			}
+		}
+	}
+	return output.String() + refinements.String()
+}
+
+// Type prints the TypeScript type of the values a schema accepts once parsed,
+// which is written out by hand for declarations that refer to themselves.
+func (p ZodPrinter) Type(s Schema, indent int) string {
+	return typePrinter{prefix: p.Prefix, parsed: true, argType: "z.ZodType<%s>"}.typ(s, indent)
+}
+
+// wraps the type of an element in brackets where it would otherwise bind
+// incorrectly, such as `(string | null)[]`.
+func arrayType(elem string) string {
+	if strings.Contains(elem, " ") {
+		elem = fmt.Sprintf("(%s)", elem)
+	}
+	return fmt.Sprintf("%s[]", elem)
+}
@@ -0,0 +1,107 @@
+package supervillain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZodPrinterSchema(t *testing.T) {
+	p := ZodPrinter{Prefix: "Api"}
+
+	assert.Equal(t,
+		`z.number().int().min(0).max(255)`,
+		p.Schema(&Primitive{Kind: PrimitiveNumber, Checks: []Check{{CheckInt, ""}, {CheckMin, "0"}, {CheckMax, "255"}}}, 0))
+	assert.Equal(t,
+		`z.coerce.bigint().min(0n)`,
+		p.Schema(&Primitive{Kind: PrimitiveBigInt, Coerce: true, Checks: []Check{{CheckMin, "0"}}}, 0))
+	assert.Equal(t,
+		`z.string().regex(/^\d+$/).ip({ version: "v4" }).startsWith("a").or(z.literal(""))`,
+		p.Schema(&OrEmpty{
+			Schema: &Primitive{Kind: PrimitiveString, Checks: []Check{{CheckRegex, `^\d+$`}, {CheckIP, "v4"}, {CheckStartsWith, "a"}}},
+			Value:  `""`,
+		}, 0))
+	assert.Equal(t,
+		`z.union([z.literal(1), z.null()])`,
+		p.Schema(&Enum{Values: []string{"1", "null"}}, 0))
+	assert.Equal(t,
+		`z.record(z.string(), z.tuple([ApiUserSchema(z.any()), z.lazy(() => ApiUserSchema)]))`,
+		p.Schema(&Record{
+			Key: &Primitive{Kind: PrimitiveString},
+			Value: &Tuple{Items: []Schema{
+				&Reference{Name: "User", Args: []Schema{&Primitive{Kind: PrimitiveAny}}},
+				&Reference{Name: "User", Lazy: true},
+			}},
+		}, 0))
+	assert.Equal(t, `z.object({
+    id: z.string().uuid().nullish().default("x"),
+    /** The name. */
+    name: z.string().min(1).optional().describe("Shown to users"),
+  })`,
+		p.Schema(&Object{Fields: []*Field{
+			{Name: "id", Schema: &Primitive{Kind: PrimitiveString}, Methods: ".uuid()", Optional: true, Nullable: true, Nullish: true, Default: `"x"`},
+			{Name: "name", Schema: &Primitive{Kind: PrimitiveString, Checks: []Check{{CheckMin, "1"}}}, Optional: true, Doc: "The name.", Description: "Shown to users"},
+		}}, 1))
+}
+
+func TestZodPrinterType(t *testing.T) {
+	p := ZodPrinter{}
+
+	assert.Equal(t, `{
+  /** The members. */
+  members?: (Node | "leaf")[] | null
+  pair: [string, unknown]
+  lookup: Record<string, Box<z.ZodType<number>>>
+}`,
+		p.Type(&Object{Fields: []*Field{
+			{Name: "members", Schema: &Array{Items: &Union{Members: []Schema{&Reference{Name: "Node"}, &Const{`"leaf"`}}}}, Optional: true, Nullable: true, Doc: "The members."},
+			{Name: "pair", Schema: &Tuple{Items: []Schema{&Primitive{Kind: PrimitiveString}, &Custom{Zod: "z.date()"}}}},
+			{Name: "lookup", Schema: &Record{Key: &Primitive{Kind: PrimitiveString}, Value: &Reference{Name: "Box", Args: []Schema{&Primitive{Kind: PrimitiveNumber}}}}},
+		}}, 0))
+}
+
+func TestZodPrinterDeclaration(t *testing.T) {
+	p := ZodPrinter{}
+
+	assert.Equal(t, `export const ShapeKindSchema = z.enum(["circle", "square"]).describe("A kind of shape.")
+/** A kind of shape. */
+export type ShapeKind = z.infer<typeof ShapeKindSchema>
+export const ShapeKind = {
+  Circle: "circle",
+  Square: "square",
+} as const`,
+		p.Declaration(&Declaration{
+			Name: "ShapeKind",
+			Schema: &Enum{
+				Values:    []string{`"circle"`, `"square"`},
+				Constants: []EnumConstant{{"Circle", `"circle"`}, {"Square", `"square"`}},
+			},
+			Doc:         "A kind of shape.",
+			Description: "A kind of shape.",
+		}))
+
+	assert.Equal(t, `export type Node = Leaf | Branch
+export const NodeSchema: z.ZodType<Node> = z.union([LeafSchema, z.lazy(() => BranchSchema)])`,
+		p.Declaration(&Declaration{
+			Name: "Node",
+			Schema: &Union{
+				Members:       []Schema{&Reference{Name: "Leaf"}, &Reference{Name: "Branch", Lazy: true}},
+				Discriminator: "kind",
+			},
+			Recursive: true,
+		}))
+
+	assert.Equal(t, `export const BoxSchema = <T1 extends z.ZodTypeAny, T2 extends z.ZodTypeAny>(t1: T1, t2: T2) => z.object({
+  Left: t1,
+  Right: t2,
+})
+export type Box<T1 extends z.ZodTypeAny, T2 extends z.ZodTypeAny> = z.infer<ReturnType<typeof BoxSchema<T1, T2>>>`,
+		p.Declaration(&Declaration{
+			Name: "Box",
+			Schema: &Object{Fields: []*Field{
+				{Name: "Left", Schema: &Param{"T1"}},
+				{Name: "Right", Schema: &Param{"T2"}},
+			}},
+			Params: []string{"T1", "T2"},
+		}))
+}
@@ -0,0 +1,90 @@
+package supervillain
+
+import (
+	"reflect"
+)
+
+// QuotedMode decides how fields with the `json:",string"` option are converted.
+// encoding/json writes these scalars as JSON strings, such as `"42"`.
+type QuotedMode int
+
+const (
+	// QuotedRefine validates the string with a pattern for the Go type, so the
+	// parsed value is still a string.
+	QuotedRefine QuotedMode = iota
+	// QuotedCoerce converts the string back to the Go type's JavaScript
+	// equivalent while parsing, so the parsed value is a number or boolean.
+	QuotedCoerce
+)
+
+type quotedModeOption QuotedMode
+
+func (q quotedModeOption) apply(c *Converter) {
+	c.quotedMode = QuotedMode(q)
+}
+
+// WithQuotedMode sets how fields with the `json:",string"` option are
+// converted, the default is QuotedRefine.
+func WithQuotedMode(m QuotedMode) Option {
+	return quotedModeOption(m)
+}
+
+// gets the type of a field that encoding/json writes as a string because of the
+// `,string` option. as with encoding/json, this applies to scalars and unnamed
+// pointers to scalars that do not marshal themselves.
+func (c *Converter) quotedType(f reflect.StructField) (reflect.Type, bool) {
+	if _, opts := parseTag(f.Tag.Get("json")); !opts.contains("string") {
+		return nil, false
+	}
+
+	t := f.Type
+	if t.Name() == "" && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if c.isCustom(t) || c.isEnum(t) {
+		return nil, false
+	}
+
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.String:
+		return t, true
+	}
+
+	return nil, false
+}
+
+func (c *Converter) convertQuoted(t reflect.Type) Schema {
+	switch t.Kind() {
+	case reflect.Bool:
+		if c.quotedMode == QuotedCoerce {
+			return &Primitive{Kind: PrimitiveBoolean, Coerce: true}
+		}
+		return &Enum{Values: []string{`"true"`, `"false"`}}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if c.quotedMode == QuotedCoerce {
+			return c.convertInteger(t, true)
+		}
+		return &Primitive{Kind: PrimitiveString, Checks: []Check{{CheckRegex, `^-?\d+$`}}}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if c.quotedMode == QuotedCoerce {
+			return c.convertInteger(t, true)
+		}
+		return &Primitive{Kind: PrimitiveString, Checks: []Check{{CheckRegex, `^\d+$`}}}
+
+	case reflect.Float32, reflect.Float64:
+		if c.quotedMode == QuotedCoerce {
+			return &Primitive{Kind: PrimitiveNumber, Coerce: true}
+		}
+		return &Primitive{Kind: PrimitiveString, Checks: []Check{{CheckRegex, `^-?\d+(\.\d+)?([eE][+-]?\d+)?$`}}}
+	}
+
+	// strings are quoted twice, but the value is still a string.
+	return &Primitive{Kind: PrimitiveString}
+}
@@ -0,0 +1,101 @@
+package supervillain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type QuotedRecord struct {
+	ID       int64    `json:"id,string"`
+	Count    uint32   `json:"count,string"`
+	Ratio    float64  `json:"ratio,string"`
+	Enabled  bool     `json:"enabled,string"`
+	Label    string   `json:"label,string"`
+	ParentID *int64   `json:"parentId,string"`
+	OwnerID  *int64   `json:"ownerId,string,omitempty"`
+	Tags     []string `json:"tags,string"`
+	State    State    `json:"state,string"`
+}
+
+func TestQuotedRefine(t *testing.T) {
+	assert.Equal(t,
+		`export const QuotedRecordSchema = z.object({
+  id: z.string().regex(/^-?\d+$/),
+  count: z.string().regex(/^\d+$/),
+  ratio: z.string().regex(/^-?\d+(\.\d+)?([eE][+-]?\d+)?$/),
+  enabled: z.enum(["true", "false"]),
+  label: z.string(),
+  parentId: z.string().regex(/^-?\d+$/).nullable(),
+  ownerId: z.string().regex(/^-?\d+$/).optional(),
+  tags: z.string().array().nullable(),
+  state: z.string(),
+})
+export type QuotedRecord = z.infer<typeof QuotedRecordSchema>
+
+`, StructToZodSchema(QuotedRecord{}))
+}
+
+func TestQuotedCoerce(t *testing.T) {
+	assert.Equal(t,
+		`export const QuotedRecordSchema = z.object({
+  id: z.coerce.number().int(),
+  count: z.coerce.number().int().min(0).max(4294967295),
+  ratio: z.coerce.number(),
+  enabled: z.enum(["true", "false"]).transform((v) => v === "true"),
+  label: z.string(),
+  parentId: z.coerce.number().int().nullable(),
+  ownerId: z.coerce.number().int().optional(),
+  tags: z.string().array().nullable(),
+  state: z.string(),
+})
+export type QuotedRecord = z.infer<typeof QuotedRecordSchema>
+
+`, StructToZodSchema(QuotedRecord{}, WithQuotedMode(QuotedCoerce)))
+}
+
+func TestQuotedMatchesEncodingJSON(t *testing.T) {
+	id := int64(-9007199254740993)
+	b, err := json.Marshal(QuotedRecord{ID: id, Count: 7, Ratio: 0.5, Enabled: true, ParentID: &id})
+	require.NoError(t, err)
+
+	raw := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(b, &raw))
+	assert.Equal(t, "-9007199254740993", raw["id"])
+	assert.Equal(t, "7", raw["count"])
+	assert.Equal(t, "0.5", raw["ratio"])
+	assert.Equal(t, "true", raw["enabled"])
+	assert.Equal(t, "-9007199254740993", raw["parentId"])
+}
+
+type QuotedTree struct {
+	ID       uint64       `json:"id,string"`
+	Visible  bool         `json:"visible,string"`
+	Children []QuotedTree `json:"children"`
+}
+
+func TestQuotedRecursive(t *testing.T) {
+	assert.Equal(t,
+		`export type QuotedTree = {
+  id: string
+  visible: "true" | "false"
+  children: QuotedTree[] | null
+}
+export const QuotedTreeSchema: z.ZodType<QuotedTree> = z.object({
+  id: z.string().regex(/^\d+$/),
+  visible: z.enum(["true", "false"]),
+  children: z.lazy(() => QuotedTreeSchema).array().nullable(),
+})
+
+`, StructToZodSchema(QuotedTree{}))
+
+	assert.Contains(t,
+		StructToZodSchema(QuotedTree{}, WithQuotedMode(QuotedCoerce)),
+		`export type QuotedTree = {
+  id: number
+  visible: boolean
+  children: QuotedTree[] | null
+}`)
+}
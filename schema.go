@@ -0,0 +1,357 @@
+package supervillain
+
+import (
+	"reflect"
+)
+
+// Schema is a node of the intermediate representation that Go types are
+// converted to before they are printed, such as an *Object or a *Reference.
+// Every node is a pointer to one of the types in this file.
+type Schema interface {
+	isSchema()
+}
+
+// PrimitiveKind is the JavaScript type of a Primitive.
+type PrimitiveKind int
+
+const (
+	PrimitiveString PrimitiveKind = iota
+	PrimitiveNumber
+	PrimitiveBoolean
+	PrimitiveBigInt
+	// PrimitiveAny is used for interfaces, which may hold any value including
+	// null.
+	PrimitiveAny
+	// PrimitiveUnknown is used where the type could not be converted.
+	PrimitiveUnknown
+)
+
+// Primitive is a scalar value, along with the checks that narrow it down.
+type Primitive struct {
+	Kind PrimitiveKind
+	// Coerce converts strings to the kind while parsing, as used for fields
+	// with the `json:",string"` option.
+	Coerce bool
+	// Format is how a string is encoded, `date-time` for time.Time and
+	// `base64` for []byte.
+	Format string
+	Checks []Check
+}
+
+// CheckKind is a constraint on the value of a Primitive or the length of an
+// Array.
+type CheckKind int
+
+const (
+	// CheckInt only accepts whole numbers.
+	CheckInt CheckKind = iota
+	// CheckNonNegative only accepts numbers that are zero or more.
+	CheckNonNegative
+	// CheckMin and CheckMax are the bounds of a number, or of the length of a
+	// string or array.
+	CheckMin
+	CheckMax
+	// CheckLength is the exact length of a string or array.
+	CheckLength
+	// CheckGreaterThan and friends are exclusive and inclusive bounds of a
+	// number.
+	CheckGreaterThan
+	CheckGreaterThanOrEqual
+	CheckLessThan
+	CheckLessThanOrEqual
+	// CheckNonZero rejects the number zero.
+	CheckNonZero
+	// CheckTrue only accepts true.
+	CheckTrue
+	CheckEmail
+	CheckURL
+	CheckUUID
+	// CheckIP accepts IP addresses of the version in its value, `v4` or `v6`,
+	// or of either version when it is empty.
+	CheckIP
+	// CheckRegex accepts strings that match the regular expression in its
+	// value, written without slashes.
+	CheckRegex
+	CheckStartsWith
+	CheckEndsWith
+	CheckIncludes
+	CheckLowercase
+	CheckUppercase
+)
+
+// Check is a constraint on a value, such as `.min(1)`. The value is a
+// number for bounds and lengths, and a plain string for patterns and
+// substrings.
+type Check struct {
+	Kind  CheckKind
+	Value string
+}
+
+// Const is a single JSON value, such as the discriminator of a union member.
+type Const struct {
+	// Value is the JSON encoding of the value, such as `"created"` or `1`.
+	Value string
+}
+
+// Enum is one of a fixed set of JSON values.
+type Enum struct {
+	// Values are the JSON encoding of each value, in the order they were
+	// declared.
+	Values []string
+	// Constants name each value for the object of values declared alongside
+	// a registered enum, when every value is named.
+	Constants []EnumConstant
+}
+
+// EnumConstant is a named value of an Enum, such as `Processing: "processing"`.
+type EnumConstant struct {
+	Name string
+	// Value is the JSON encoding of the value.
+	Value string
+}
+
+// Object is a struct, with its fields in the order encoding/json writes them.
+type Object struct {
+	Fields []*Field
+}
+
+// Field is a property of an Object.
+type Field struct {
+	// Name is the JSON name of the field.
+	Name   string
+	Schema Schema
+	// Optional fields may be missing, and Nullable fields may be null.
+	Optional bool
+	Nullable bool
+	// Nullish is set when the field was made optional and nullable by the
+	// `zod` tag, which prints as `.nullish()`.
+	Nullish bool
+	// Methods are Zod methods from the `zod` tag, such as `.email()`, which
+	// are added to the schema as they are.
+	Methods string
+	// Default is the JavaScript expression for the default value, if any.
+	Default string
+	// Doc is the Go doc comment of the field and Description the text to
+	// describe it with, which the `zod` tag may override.
+	Doc         string
+	Description string
+}
+
+// Array is a list of items, such as a slice.
+type Array struct {
+	Items  Schema
+	Checks []Check
+}
+
+// Tuple is a fixed size list with a schema for each index, such as an array
+// with ArrayTuple.
+type Tuple struct {
+	Items []Schema
+}
+
+// Record is an object with arbitrary keys, such as a map.
+type Record struct {
+	Key   Schema
+	Value Schema
+}
+
+// Union is any one of its members, such as a registered interface.
+type Union struct {
+	Members []Schema
+	// Discriminator is the JSON name of the field that tells the members
+	// apart, if any.
+	Discriminator string
+}
+
+// OrEmpty accepts the empty value of a type as well as its schema, as used for
+// validation rules with `omitempty`.
+type OrEmpty struct {
+	Schema Schema
+	// Value is the JSON encoding of the empty value, such as `""`.
+	Value string
+}
+
+// Reference refers to another Declaration.
+type Reference struct {
+	// Key identifies the declaration by the full package path and name of its
+	// Go type, and Name is the name it is declared with.
+	Key  string
+	Name string
+	// Lazy is set for references to a declaration that is not declared yet
+	// because it refers back to where it is used.
+	Lazy bool
+	// Args are the type arguments of a generic declaration.
+	Args []Schema
+}
+
+// Param is a type parameter of a generic declaration, such as `T`.
+type Param struct {
+	Name string
+}
+
+// Custom is a schema from a ZodSchema method, a CustomFn or the `zod` tag.
+// Its contents are opaque, so other backends can only use what the type
+// supplies for them and otherwise treat it as unknown.
+type Custom struct {
+	// Type is the Go type the schema is for, if it came from the type rather
+	// than a tag.
+	Type reflect.Type
+	Zod  string
+	// JSONSchema is the JSON Schema of the type, if it supplied one.
+	JSONSchema string
+	// Libraries are the schemas of the type for other libraries, keyed by the
+	// name of the library, if it supplied them.
+	Libraries map[string]string
+}
+
+func (*Primitive) isSchema() {}
+func (*Const) isSchema()     {}
+func (*Enum) isSchema()      {}
+func (*Object) isSchema()    {}
+func (*Array) isSchema()     {}
+func (*Tuple) isSchema()     {}
+func (*Record) isSchema()    {}
+func (*Union) isSchema()     {}
+func (*OrEmpty) isSchema()   {}
+func (*Reference) isSchema() {}
+func (*Param) isSchema()     {}
+func (*Custom) isSchema()    {}
+
+// Declaration is a named schema, which is declared once and used by others
+// through a Reference.
+type Declaration struct {
+	// Key identifies the declaration by the full package path and name of its
+	// Go type.
+	Key  string
+	Name string
+	Type reflect.Type
+	// Schema is what the declaration is, usually an *Object.
+	Schema Schema
+	// Params are the type parameters of a generic declaration, which its
+	// schema uses with Param.
+	Params []string
+	// Recursive is set when the schema refers back to itself, in which case
+	// some of its references are Lazy.
+	Recursive bool
+	// Cycle holds the declarations written out after this one that its schema
+	// reaches, directly or through each other, keyed like Key. It is only set
+	// on recursive declarations, for printers that write them out in place
+	// instead of referring to schemas that are not declared yet.
+	Cycle map[string]*Declaration
+	// Doc is the Go doc comment of the type and Description the text to
+	// describe it with.
+	Doc         string
+	Description string
+}
+
+// Declarations converts the inputs like ConvertSlice, but returns the
+// declarations instead of printing them, in the order they would be written
+// out. Each one comes after the declarations it depends on.
+func (c *Converter) Declarations(inputs []interface{}) []*Declaration {
+	for _, input := range inputs {
+		c.convertTopLevel(reflect.TypeOf(input))
+	}
+
+	return c.declarations(c.outputs)
+}
+
+// DeclarationsE is like Declarations but returns every problem found in the
+// types as a ConversionErrors instead of panicking on the first one.
+func (c *Converter) DeclarationsE(inputs []interface{}) ([]*Declaration, error) {
+	var decls []*Declaration
+	_, err := c.collectErrors(func() string {
+		decls = c.Declarations(inputs)
+		return ""
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decls, nil
+}
+
+func (c *Converter) declarations(outputs map[string]entry) []*Declaration {
+	// types that collide are renamed when the collision is found, which may be
+	// after their declarations or references to them were built.
+	for key, e := range outputs {
+		if name, ok := c.names[key]; ok && e.name != name {
+			e.name = name
+			e.decl.Name = name
+			outputs[key] = e
+		}
+		walkSchema(e.decl.Schema, func(s Schema) {
+			if ref, ok := s.(*Reference); ok {
+				if name, ok := c.names[ref.Key]; ok {
+					ref.Name = name
+				}
+			}
+		})
+	}
+
+	decls := []*Declaration{}
+	index := map[string]int{}
+	for _, key := range sortSchemas(outputs) {
+		index[key] = len(decls)
+		decls = append(decls, outputs[key].decl)
+	}
+
+	// recursive declarations note the ones after them that they reach, which
+	// are not declared yet where they are.
+	for i, d := range decls {
+		d.Cycle = nil
+		if !d.Recursive {
+			continue
+		}
+		pending := []*Declaration{d}
+		for len(pending) > 0 {
+			next := pending[0]
+			pending = pending[1:]
+			walkSchema(next.Schema, func(s Schema) {
+				ref, ok := s.(*Reference)
+				if !ok {
+					return
+				}
+				j, ok := index[ref.Key]
+				if !ok || j <= i || d.Cycle[ref.Key] != nil {
+					return
+				}
+				if d.Cycle == nil {
+					d.Cycle = map[string]*Declaration{}
+				}
+				d.Cycle[ref.Key] = decls[j]
+				pending = append(pending, decls[j])
+			})
+		}
+	}
+	return decls
+}
+
+// calls fn for every schema within s, including s itself, without following
+// references into other declarations.
+func walkSchema(s Schema, fn func(Schema)) {
+	fn(s)
+	switch s := s.(type) {
+	case *Object:
+		for _, f := range s.Fields {
+			walkSchema(f.Schema, fn)
+		}
+	case *Array:
+		walkSchema(s.Items, fn)
+	case *Tuple:
+		for _, item := range s.Items {
+			walkSchema(item, fn)
+		}
+	case *Record:
+		walkSchema(s.Key, fn)
+		walkSchema(s.Value, fn)
+	case *Union:
+		for _, m := range s.Members {
+			walkSchema(m, fn)
+		}
+	case *OrEmpty:
+		walkSchema(s.Schema, fn)
+	case *Reference:
+		for _, arg := range s.Args {
+			walkSchema(arg, fn)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package supervillain
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeclarations(t *testing.T) {
+	type Tag struct {
+		Label string `validate:"required,max=20"`
+	}
+	type Post struct {
+		Title  string   `json:"title"`
+		Tags   []Tag    `json:"tags,omitempty"`
+		Rating *float64 `json:"rating"`
+	}
+
+	c := NewConverter(nil, WithValidationTags("validate"))
+	decls := c.Declarations([]interface{}{Post{}})
+	require.Len(t, decls, 2)
+
+	tagKey := typeKey(reflect.TypeOf(Tag{}))
+	assert.Equal(t, &Declaration{
+		Key:  tagKey,
+		Name: "Tag",
+		Type: reflect.TypeOf(Tag{}),
+		Schema: &Object{Fields: []*Field{
+			{Name: "Label", Schema: &Primitive{Kind: PrimitiveString, Checks: []Check{{CheckMin, "1"}, {CheckMax, "20"}}}},
+		}},
+	}, decls[0])
+
+	assert.Equal(t, &Declaration{
+		Key:  typeKey(reflect.TypeOf(Post{})),
+		Name: "Post",
+		Type: reflect.TypeOf(Post{}),
+		Schema: &Object{Fields: []*Field{
+			{Name: "title", Schema: &Primitive{Kind: PrimitiveString}},
+			{Name: "tags", Schema: &Array{Items: &Reference{Key: tagKey, Name: "Tag"}}, Optional: true},
+			{Name: "rating", Schema: &Primitive{Kind: PrimitiveNumber}, Nullable: true},
+		}},
+	}, decls[1])
+}
+
+func TestDeclarationsRecursive(t *testing.T) {
+	c := NewConverter(nil)
+	decls := c.Declarations([]interface{}{Comment{}})
+	require.Len(t, decls, 1)
+
+	key := typeKey(reflect.TypeOf(Comment{}))
+	assert.True(t, decls[0].Recursive)
+	assert.Equal(t,
+		&Array{Items: &Reference{Key: key, Name: "Comment", Lazy: true}},
+		decls[0].Schema.(*Object).Fields[1].Schema)
+}
+
+func TestDeclarationsCycle(t *testing.T) {
+	c := NewConverter(nil)
+	decls := c.Declarations([]interface{}{Organisation{}})
+	require.Len(t, decls, 3)
+
+	assert.Equal(t, "Employee", decls[0].Name)
+	assert.Equal(t, map[string]*Declaration{decls[1].Key: decls[1]}, decls[0].Cycle)
+	assert.Equal(t, "Department", decls[1].Name)
+	assert.Nil(t, decls[1].Cycle)
+	assert.Nil(t, decls[2].Cycle)
+}
+
+func TestDeclarationsE(t *testing.T) {
+	type Bad struct {
+		C chan int
+	}
+
+	c := NewConverter(nil)
+	decls, err := c.DeclarationsE([]interface{}{Bad{}})
+	assert.Nil(t, decls)
+	assert.EqualError(t, err, "Bad.C: cannot handle: chan (unsupported_type)")
+}
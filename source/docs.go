@@ -0,0 +1,132 @@
+package source
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Docs is the doc comments of every type found in a set of packages, keyed by
+// the full package path and name of the type. It is a DocSource, so it can be
+// passed to supervillain.WithDocSource.
+type Docs map[string]TypeDoc
+
+// TypeDoc is the doc comment of a type and the doc comments of its fields, by
+// their Go names.
+type TypeDoc struct {
+	Doc    string
+	Fields map[string]string
+}
+
+// LoadDocs loads the packages matching the patterns, as understood by `go
+// list`, and finds the doc comments of every type declared in them and of the
+// fields of struct types. A field without a doc comment uses its line comment
+// instead.
+func LoadDocs(patterns ...string) (Docs, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName | packages.NeedSyntax | packages.NeedTypes}, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := Docs{}
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, fmt.Errorf("loading %s: %w", pkg.PkgPath, pkg.Errors[0])
+		}
+		for _, file := range pkg.Syntax {
+			docs.add(pkg.PkgPath, file)
+		}
+	}
+
+	return docs, nil
+}
+
+func (d Docs) add(pkgPath string, file *ast.File) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			spec := spec.(*ast.TypeSpec)
+
+			// a lone type declaration has its doc comment on the declaration
+			// rather than the spec.
+			doc := spec.Doc
+			if doc == nil && len(gen.Specs) == 1 {
+				doc = gen.Doc
+			}
+
+			typeDoc := TypeDoc{Doc: doc.Text(), Fields: map[string]string{}}
+			if st, ok := spec.Type.(*ast.StructType); ok {
+				for _, field := range st.Fields.List {
+					text := field.Doc.Text()
+					if text == "" {
+						text = field.Comment.Text()
+					}
+					if text == "" {
+						continue
+					}
+					for _, name := range fieldNames(field) {
+						typeDoc.Fields[name] = text
+					}
+				}
+			}
+
+			d[fmt.Sprintf("%s.%s", pkgPath, spec.Name.Name)] = typeDoc
+		}
+	}
+}
+
+// gets the Go names of a field, which for embedded fields is the name of the
+// embedded type.
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) > 0 {
+		names := make([]string, len(field.Names))
+		for i, name := range field.Names {
+			names[i] = name.Name
+		}
+		return names
+	}
+
+	typ := field.Type
+	for {
+		switch t := typ.(type) {
+		case *ast.StarExpr:
+			typ = t.X
+		case *ast.SelectorExpr:
+			return []string{t.Sel.Name}
+		case *ast.IndexExpr:
+			typ = t.X
+		case *ast.IndexListExpr:
+			typ = t.X
+		case *ast.Ident:
+			return []string{t.Name}
+		default:
+			return nil
+		}
+	}
+}
+
+// gets the doc comments of a type, looked up by its name without any type
+// arguments so that instances of generic types share the doc comments of the
+// generic type.
+func (d Docs) lookup(t reflect.Type) TypeDoc {
+	name, _, _ := strings.Cut(t.Name(), "[")
+	return d[fmt.Sprintf("%s.%s", t.PkgPath(), name)]
+}
+
+// TypeDoc gets the doc comment of a named type.
+func (d Docs) TypeDoc(t reflect.Type) string {
+	return d.lookup(t).Doc
+}
+
+// FieldDoc gets the doc comment of a field of a named struct type.
+func (d Docs) FieldDoc(t reflect.Type, field string) string {
+	return d.lookup(t).Fields[field]
+}
@@ -0,0 +1,91 @@
+package source
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Southclaws/supervillain"
+	"github.com/Southclaws/supervillain/source/internal/fixtures/articles"
+)
+
+const articlesFixtures = "github.com/Southclaws/supervillain/source/internal/fixtures/articles"
+
+func TestLoadDocs(t *testing.T) {
+	docs, err := LoadDocs(articlesFixtures)
+	require.NoError(t, err)
+
+	assert.Equal(t, Docs{
+		articlesFixtures + ".Article": {
+			Doc: "Article is a piece of writing.\n\nArticles are written by an Author and may be\npublished at any time.\n",
+			Fields: map[string]string{
+				"Title": "Title is shown at the top of the page.\n",
+				"Body":  "the text, as Markdown\n",
+				"Slug":  "Slug is the address of the article.\n\nDeprecated: use ID instead.\n",
+			},
+		},
+		articlesFixtures + ".Meta": {
+			Doc: "Meta is added to every article.\n",
+			Fields: map[string]string{
+				"Views": "Views is the number of times the article was read.\n",
+			},
+		},
+		articlesFixtures + ".Author": {
+			Doc:    "Author writes articles.\n",
+			Fields: map[string]string{},
+		},
+		articlesFixtures + ".Editor": {
+			Doc:    "Deprecated: articles no longer have editors.\n",
+			Fields: map[string]string{},
+		},
+	}, docs)
+}
+
+func TestLoadDocsError(t *testing.T) {
+	_, err := LoadDocs("github.com/Southclaws/supervillain/source/internal/fixtures/missing")
+	assert.Error(t, err)
+}
+
+func TestDocSource(t *testing.T) {
+	docs, err := LoadDocs(articlesFixtures)
+	require.NoError(t, err)
+
+	c := supervillain.NewConverter(nil, supervillain.WithDocSource(docs))
+	assert.Equal(t,
+		`export const ArticleSchema = z.object({
+  /** Title is shown at the top of the page. */
+  Title: z.string().describe("Title is shown at the top of the page."),
+  /** the text, as Markdown */
+  Body: z.string().describe("the text, as Markdown"),
+  /**
+   * Slug is the address of the article.
+   * @deprecated use ID instead.
+   */
+  slug: z.string().describe("Slug is the address of the article."),
+  ID: z.number().int(),
+  /** Views is the number of times the article was read. */
+  Views: z.number().int().describe("Views is the number of times the article was read."),
+}).describe("Article is a piece of writing.\n\nArticles are written by an Author and may be published at any time.")
+/**
+ * Article is a piece of writing.
+ *
+ * Articles are written by an Author and may be
+ * published at any time.
+ */
+export type Article = z.infer<typeof ArticleSchema>
+
+`,
+		c.Convert(articles.Article{}))
+
+	c = supervillain.NewConverter(nil, supervillain.WithDocSource(docs))
+	assert.Equal(t,
+		`export const EditorSchema = z.object({
+  Name: z.string(),
+})
+/** @deprecated articles no longer have editors. */
+export type Editor = z.infer<typeof EditorSchema>
+
+`,
+		c.Convert(articles.Editor{}))
+}
@@ -0,0 +1,224 @@
+// Package source finds what reflection cannot see about Go types by loading
+// their source code, such as the constants that make up an enum.
+package source
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/Southclaws/supervillain"
+)
+
+// Enums is every enum type found in a set of packages, keyed by the full
+// package path and name of the type. It is an EnumSource, so it can be passed
+// to supervillain.WithEnumSource.
+type Enums map[string]Enum
+
+// Enum is a named type along with the exported constants of that type that are
+// declared in the same package.
+type Enum struct {
+	PkgPath   string
+	Name      string
+	Constants []Constant
+}
+
+// Constant is a single exported constant of an enum type.
+type Constant struct {
+	Name  string
+	Value constant.Value
+}
+
+// the comment that marks a type as an enum even though it has only one
+// constant.
+const enumAnnotation = "//supervillain:enum"
+
+// LoadEnums loads the packages matching the patterns, as understood by `go
+// list`, and finds every named type that has at least two exported constants
+// declared in the same package. Constants are kept in the order they are
+// declared. A type with a single constant, such as `const KB Bytes = 1024`, is
+// only an enum if its declaration has a //supervillain:enum comment.
+func LoadEnums(patterns ...string) (Enums, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName | packages.NeedSyntax | packages.NeedTypes}, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	enums := Enums{}
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, fmt.Errorf("loading %s: %w", pkg.PkgPath, pkg.Errors[0])
+		}
+		enums.add(pkg.Types, annotatedTypes(pkg.Syntax))
+	}
+
+	return enums, nil
+}
+
+func (e Enums) add(pkg *types.Package, annotated map[string]bool) {
+	consts := []*types.Const{}
+	for _, name := range pkg.Scope().Names() {
+		c, ok := pkg.Scope().Lookup(name).(*types.Const)
+		if !ok || !c.Exported() {
+			continue
+		}
+		named, ok := c.Type().(*types.Named)
+		if !ok || named.Obj().Pkg() != pkg {
+			continue
+		}
+		consts = append(consts, c)
+	}
+
+	// the scope is sorted by name, but the declared order is more meaningful.
+	sort.Slice(consts, func(i, j int) bool {
+		return consts[i].Pos() < consts[j].Pos()
+	})
+
+	found := Enums{}
+	for _, c := range consts {
+		typ := c.Type().(*types.Named).Obj()
+		key := fmt.Sprintf("%s.%s", pkg.Path(), typ.Name())
+
+		enum, ok := found[key]
+		if !ok {
+			enum = Enum{PkgPath: pkg.Path(), Name: typ.Name()}
+		}
+		enum.Constants = append(enum.Constants, Constant{Name: c.Name(), Value: c.Val()})
+		found[key] = enum
+	}
+
+	for key, enum := range found {
+		if len(enum.Constants) > 1 || annotated[enum.Name] {
+			e[key] = enum
+		}
+	}
+}
+
+// finds the names of the types in a package that are marked as enums.
+func annotatedTypes(files []*ast.File) map[string]bool {
+	annotated := map[string]bool{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.TypeSpec)
+				// a lone type declaration has its doc comment on the
+				// declaration rather than the spec.
+				doc := spec.Doc
+				if doc == nil && len(gen.Specs) == 1 {
+					doc = gen.Doc
+				}
+				if hasAnnotation(doc) {
+					annotated[spec.Name.Name] = true
+				}
+			}
+		}
+	}
+	return annotated
+}
+
+func hasAnnotation(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		if strings.TrimSpace(c.Text) == enumAnnotation {
+			return true
+		}
+	}
+	return false
+}
+
+// EnumValues builds a value of t for each constant of its enum, if it has one.
+// The values are marshalled by the converter, so any MarshalJSON or
+// MarshalText method of the type decides how they are written. Otherwise a
+// String method gives the value, and without one the constant is written as it
+// is.
+func (e Enums) EnumValues(t reflect.Type) ([]supervillain.EnumValue, bool) {
+	enum, ok := e[fmt.Sprintf("%s.%s", t.PkgPath(), t.Name())]
+	if !ok {
+		return nil, false
+	}
+
+	values := []supervillain.EnumValue{}
+	for _, c := range enum.Constants {
+		v := reflect.New(t).Elem()
+		if !setConstant(v, c.Value) {
+			return nil, false
+		}
+		values = append(values, supervillain.EnumValue{Name: c.Name, Value: v.Interface(), JSON: stringJSON(v)})
+	}
+
+	return values, true
+}
+
+// gets the JSON of a constant from its String method, as long as the type has
+// no MarshalJSON or MarshalText method that the converter would use instead.
+func stringJSON(v reflect.Value) string {
+	for _, x := range []interface{}{v.Interface(), v.Addr().Interface()} {
+		switch x.(type) {
+		case json.Marshaler, encoding.TextMarshaler:
+			return ""
+		}
+	}
+	for _, x := range []interface{}{v.Interface(), v.Addr().Interface()} {
+		if s, ok := x.(fmt.Stringer); ok {
+			b, _ := json.Marshal(s.String())
+			return string(b)
+		}
+	}
+	return ""
+}
+
+// sets v to the value of a constant, as long as the constant is exact in the
+// kind of v.
+func setConstant(v reflect.Value, c constant.Value) bool {
+	switch v.Kind() {
+	case reflect.Bool:
+		if c.Kind() != constant.Bool {
+			return false
+		}
+		v.SetBool(constant.BoolVal(c))
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, exact := constant.Int64Val(constant.ToInt(c))
+		if !exact {
+			return false
+		}
+		v.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, exact := constant.Uint64Val(constant.ToInt(c))
+		if !exact {
+			return false
+		}
+		v.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		f, _ := constant.Float64Val(constant.ToFloat(c))
+		v.SetFloat(f)
+
+	case reflect.String:
+		if c.Kind() != constant.String {
+			return false
+		}
+		v.SetString(constant.StringVal(c))
+
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,129 @@
+package source
+
+import (
+	"go/constant"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Southclaws/supervillain"
+	"github.com/Southclaws/supervillain/source/internal/fixtures/status"
+)
+
+const fixtures = "github.com/Southclaws/supervillain/source/internal/fixtures/status"
+
+func TestLoadEnums(t *testing.T) {
+	enums, err := LoadEnums(fixtures)
+	require.NoError(t, err)
+
+	assert.Equal(t, Enums{
+		fixtures + ".Status": {
+			PkgPath: fixtures,
+			Name:    "Status",
+			Constants: []Constant{
+				{Name: "StatusDraft", Value: constant.MakeInt64(0)},
+				{Name: "StatusPublished", Value: constant.MakeInt64(1)},
+				{Name: "StatusArchived", Value: constant.MakeInt64(2)},
+				{Name: "Deleted", Value: constant.MakeInt64(10)},
+				{Name: "StatusRemoved", Value: constant.MakeInt64(10)},
+			},
+		},
+		fixtures + ".Visibility": {
+			PkgPath: fixtures,
+			Name:    "Visibility",
+			Constants: []Constant{
+				{Name: "VisibilityPrivate", Value: constant.MakeInt64(0)},
+				{Name: "VisibilityPublic", Value: constant.MakeInt64(1)},
+			},
+		},
+		fixtures + ".Colour": {
+			PkgPath: fixtures,
+			Name:    "Colour",
+			Constants: []Constant{
+				{Name: "ColourRed", Value: constant.MakeString("red")},
+				{Name: "ColourGreen", Value: constant.MakeString("green")},
+			},
+		},
+		fixtures + ".Priority": {
+			PkgPath: fixtures,
+			Name:    "Priority",
+			Constants: []Constant{
+				{Name: "PriorityLow", Value: constant.MakeInt64(0)},
+				{Name: "PriorityHigh", Value: constant.MakeInt64(1)},
+			},
+		},
+		fixtures + ".Kind": {
+			PkgPath: fixtures,
+			Name:    "Kind",
+			Constants: []Constant{
+				{Name: "KindArticle", Value: constant.MakeString("article")},
+			},
+		},
+	}, enums)
+
+	// a single constant is a unit, not an enum, unless the type is marked.
+	assert.NotContains(t, enums, fixtures+".Bytes")
+}
+
+func TestLoadEnumsError(t *testing.T) {
+	_, err := LoadEnums("github.com/Southclaws/supervillain/source/internal/fixtures/missing")
+	assert.Error(t, err)
+}
+
+func TestEnumSource(t *testing.T) {
+	enums, err := LoadEnums(fixtures)
+	require.NoError(t, err)
+
+	c := supervillain.NewConverter(nil, supervillain.WithEnumSource(enums))
+	assert.Equal(t,
+		`export const ColourSchema = z.enum(["red", "green"])
+export type Colour = z.infer<typeof ColourSchema>
+export const Colour = {
+  Red: "red",
+  Green: "green",
+} as const
+
+export const KindSchema = z.enum(["article"])
+export type Kind = z.infer<typeof KindSchema>
+export const Kind = {
+  Article: "article",
+} as const
+
+export const PrioritySchema = z.enum(["low", "high"])
+export type Priority = z.infer<typeof PrioritySchema>
+export const Priority = {
+  Low: "low",
+  High: "high",
+} as const
+
+export const StatusSchema = z.union([z.literal(0), z.literal(1), z.literal(2), z.literal(10)])
+export type Status = z.infer<typeof StatusSchema>
+export const Status = {
+  Draft: 0,
+  Published: 1,
+  Archived: 2,
+  Deleted: 10,
+  Removed: 10,
+} as const
+
+export const VisibilitySchema = z.enum(["private", "public"])
+export type Visibility = z.infer<typeof VisibilitySchema>
+export const Visibility = {
+  Private: "private",
+  Public: "public",
+} as const
+
+export const PostSchema = z.object({
+  Status: StatusSchema,
+  Visibility: VisibilitySchema,
+  Colour: ColourSchema.nullable(),
+  Priority: PrioritySchema,
+  Kind: KindSchema,
+  Size: z.number().int(),
+})
+export type Post = z.infer<typeof PostSchema>
+
+`,
+		c.Convert(status.Post{}))
+}
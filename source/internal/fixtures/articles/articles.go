@@ -0,0 +1,35 @@
+package articles
+
+// Article is a piece of writing.
+//
+// Articles are written by an Author and may be
+// published at any time.
+type Article struct {
+	// Title is shown at the top of the page.
+	Title string
+	Body  string // the text, as Markdown
+	// Slug is the address of the article.
+	//
+	// Deprecated: use ID instead.
+	Slug string `json:"slug"`
+	ID   int
+	Meta
+}
+
+// Meta is added to every article.
+type Meta struct {
+	// Views is the number of times the article was read.
+	Views int
+}
+
+type (
+	// Author writes articles.
+	Author struct {
+		Name string
+	}
+
+	// Deprecated: articles no longer have editors.
+	Editor struct {
+		Name string
+	}
+)
@@ -0,0 +1,84 @@
+package status
+
+type Status int
+
+const (
+	StatusDraft Status = iota
+	StatusPublished
+	StatusArchived
+)
+
+const (
+	// Deleted is not prefixed with the type name.
+	Deleted Status = 10
+	// StatusRemoved is an alias of Deleted.
+	StatusRemoved = Deleted
+
+	statusHidden Status = 20
+)
+
+// Visibility is written by its String method.
+type Visibility int
+
+const (
+	VisibilityPrivate Visibility = iota
+	VisibilityPublic
+)
+
+func (v Visibility) String() string {
+	if v == VisibilityPublic {
+		return "public"
+	}
+	return "private"
+}
+
+func (v Visibility) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+type Colour string
+
+const (
+	ColourRed   Colour = "red"
+	ColourGreen Colour = "green"
+)
+
+// Priority is written by its String method alone.
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityHigh
+)
+
+func (p Priority) String() string {
+	if p == PriorityHigh {
+		return "high"
+	}
+	return "low"
+}
+
+// Bytes has a single constant, which is a unit rather than an enum.
+type Bytes int
+
+const KB Bytes = 1024
+
+// Kind has a single constant but is marked as an enum.
+//
+//supervillain:enum
+type Kind string
+
+const KindArticle Kind = "article"
+
+// Post uses every enum.
+type Post struct {
+	Status     Status
+	Visibility Visibility
+	Colour     *Colour
+	Priority   Priority
+	Kind       Kind
+	Size       Bytes
+}
+
+// Limit is a constant of a basic type so it is not an enum.
+const Limit = 10
@@ -0,0 +1,408 @@
+package supervillain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// TypeBoxPrinter prints declarations as TypeBox schemas and the types that are
+// inferred from them with Static. TypeBox schemas are JSON Schema, so like
+// JSONSchemaPrinter they describe the JSON that encoding/json writes: fields
+// with the `,string` option are strings and checks are the same keywords.
+//
+// Declarations that refer back to themselves have an `$id` of their name, and
+// are wrapped in Type.Recursive. Where several refer to each other, the one
+// declared first writes out the others in place, so every schema can be
+// validated on its own. Only generic declarations, and those in other modules,
+// are instead referred to with Type.Ref, which validators must be given the
+// other schema to resolve.
+//
+// Zod methods from the `zod` tag have no TypeBox equivalent so they are left
+// out, and custom schemas from the tag are `Type.Unknown()`.
+type TypeBoxPrinter struct {
+	// Prefix is added to the start of every declared name.
+	Prefix string
+
+	// the key of the recursive declaration being printed, which refers to
+	// itself with `This`.
+	self string
+	// the declarations after it that it reaches, which are written out in
+	// place, and the keys of those being written out.
+	cycle    map[string]*Declaration
+	inlining []string
+}
+
+// Name is "typebox".
+func (p TypeBoxPrinter) Name() string {
+	return "typebox"
+}
+
+// Import imports TypeBox, along with the types that the schemas use.
+func (p TypeBoxPrinter) Import(Preamble) string {
+	return "import { Type, type Static, type TSchema } from \"@sinclair/typebox\"\n"
+}
+
+// Declaration prints the schema and type of a declaration, along with the
+// object of values of an enum.
+func (p TypeBoxPrinter) Declaration(d *Declaration) string {
+	name := p.Prefix + d.Name
+	schemaName := schemaName(p.Prefix, d.Name)
+	jsdoc, _ := formatDoc(d.Doc, 0)
+
+	options := &jsonObject{}
+	if d.Recursive {
+		options.set("$id", name)
+	}
+	if d.Description != "" {
+		options.set("description", d.Description)
+	}
+
+	output := strings.Builder{}
+	switch {
+	case len(d.Params) > 0:
+		typeParams := make([]string, len(d.Params))
+		valueParams := make([]string, len(d.Params))
+		for i, param := range d.Params {
+			typeParams[i] = fmt.Sprintf("%s extends TSchema", param)
+			valueParams[i] = fmt.Sprintf("%s: %s", strings.ToLower(param), param)
+		}
+		output.WriteString(fmt.Sprintf("export const %s = <%s>(%s) => %s\n",
+			schemaName, strings.Join(typeParams, ", "), strings.Join(valueParams, ", "), p.schema(d.Schema, 0, options)))
+		output.WriteString(jsdoc)
+		output.WriteString(fmt.Sprintf("export type %s<%s> = Static<ReturnType<typeof %s<%s>>>",
+			name, strings.Join(typeParams, ", "), schemaName, strings.Join(d.Params, ", ")))
+
+	case d.Recursive:
+		// Static cannot follow references between declarations that refer to
+		// each other so the type is written out by hand.
+		output.WriteString(jsdoc)
+		output.WriteString(fmt.Sprintf("export type %s = %s\n", name, p.Type(d.Schema, 0)))
+		if refersToSelf(d) || len(d.Cycle) > 0 {
+			p.self = d.Key
+			p.cycle = d.Cycle
+			output.WriteString(fmt.Sprintf("export const %s = Type.Recursive((This) => %s, %s)",
+				schemaName, p.schema(d.Schema, 0, nil), jsObject(options)))
+		} else {
+			output.WriteString(fmt.Sprintf("export const %s = %s", schemaName, p.schema(d.Schema, 0, options)))
+		}
+
+	default:
+		output.WriteString(fmt.Sprintf("export const %s = %s\n", schemaName, p.schema(d.Schema, 0, options)))
+		output.WriteString(jsdoc)
+		output.WriteString(fmt.Sprintf("export type %s = Static<typeof %s>", name, schemaName))
+	}
+
+	output.WriteString(enumObject(name, d.Schema))
+
+	return output.String()
+}
+
+// Schema prints a schema as a TypeBox expression. Objects are indented by the
+// given level, as they would be when written inside other objects at it.
+func (p TypeBoxPrinter) Schema(s Schema, indent int) string {
+	return p.schema(s, indent, nil)
+}
+
+// prints a schema with options, such as its description, which are the last
+// argument of the Type function that builds it.
+func (p TypeBoxPrinter) schema(s Schema, indent int, options *jsonObject) string {
+	switch s := s.(type) {
+	case *Primitive:
+		return p.primitive(s, options)
+
+	case *Const:
+		return typeBoxLiteral(s.Value, options)
+
+	case *Enum:
+		if len(s.Values) == 1 {
+			return typeBoxLiteral(s.Values[0], options)
+		}
+		literals := make([]string, len(s.Values))
+		for i, v := range s.Values {
+			literals[i] = typeBoxLiteral(v, nil)
+		}
+		return typeBoxCall("Union", options, fmt.Sprintf("[%s]", strings.Join(literals, ", ")))
+
+	case *Object:
+		output := strings.Builder{}
+		output.WriteString("{\n")
+		for _, f := range s.Fields {
+			output.WriteString(p.field(f, indent+1))
+		}
+		output.WriteString(indentation(indent))
+		output.WriteString("}")
+		return typeBoxCall("Object", options, output.String())
+
+	case *Array:
+		options = options.copyOrEmpty()
+		for _, check := range s.Checks {
+			switch check.Kind {
+			case CheckMin:
+				options.set("minItems", json.Number(check.Value))
+			case CheckMax:
+				options.set("maxItems", json.Number(check.Value))
+			case CheckLength:
+				options.set("minItems", json.Number(check.Value))
+				options.set("maxItems", json.Number(check.Value))
+			}
+		}
+		return typeBoxCall("Array", options, p.schema(s.Items, indent, nil))
+
+	case *Tuple:
+		items := make([]string, len(s.Items))
+		for i, item := range s.Items {
+			items[i] = p.schema(item, indent, nil)
+		}
+		return typeBoxCall("Tuple", options, fmt.Sprintf("[%s]", strings.Join(items, ", ")))
+
+	case *Record:
+		return typeBoxCall("Record", options, p.schema(s.Key, indent, nil), p.schema(s.Value, indent, nil))
+
+	case *Union:
+		if len(s.Members) == 1 {
+			return p.schema(s.Members[0], indent, options)
+		}
+		members := make([]string, len(s.Members))
+		for i, m := range s.Members {
+			members[i] = p.schema(m, indent, nil)
+		}
+		return typeBoxCall("Union", options, fmt.Sprintf("[%s]", strings.Join(members, ", ")))
+
+	case *OrEmpty:
+		return typeBoxCall("Union", options, fmt.Sprintf("[%s, %s]", p.schema(s.Schema, indent, nil), typeBoxLiteral(s.Value, nil)))
+
+	case *Reference:
+		name := schemaName(p.Prefix, s.Name)
+		target := p.cycle[s.Key]
+		switch {
+		case p.self != "" && s.Key == p.self:
+			name = "This"
+		case p.isInlining(s.Key):
+			name = "This" + p.Prefix + s.Name
+		case target != nil && len(target.Params) == 0:
+			return p.inline(target, indent, options)
+		case s.Lazy:
+			name = fmt.Sprintf("Type.Unsafe<%s>(Type.Ref(%s))", p.Prefix+s.Name, jsString(p.Prefix+s.Name))
+		case len(s.Args) > 0:
+			args := make([]string, len(s.Args))
+			for i, arg := range s.Args {
+				args[i] = p.schema(arg, indent, nil)
+			}
+			name = fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+		}
+		return typeBoxSpread(name, options)
+
+	case *Param:
+		return typeBoxSpread(strings.ToLower(s.Name), options)
+
+	case *Custom:
+		if schema, ok := s.Libraries[p.Name()]; ok {
+			return typeBoxSpread(schema, options)
+		}
+	}
+
+	return typeBoxCall("Unknown", options)
+}
+
+func (p TypeBoxPrinter) field(f *Field, indent int) string {
+	jsdoc, _ := formatDoc(f.Doc, indent)
+
+	options := &jsonObject{}
+	if f.Default != "" {
+		// the default is a JavaScript expression, which is written as it is.
+		options.set("default", json.RawMessage(f.Default))
+	}
+	if f.Description != "" {
+		options.set("description", f.Description)
+	}
+
+	schema := ""
+	if f.Nullable || f.Nullish {
+		schema = typeBoxCall("Union", options, fmt.Sprintf("[%s, Type.Null()]", p.schema(f.Schema, indent, nil)))
+	} else {
+		schema = p.schema(f.Schema, indent, options)
+	}
+	if f.Optional || f.Nullish {
+		schema = fmt.Sprintf("Type.Optional(%s)", schema)
+	}
+
+	return fmt.Sprintf("%s%s%s: %s,\n", jsdoc, indentation(indent), f.Name, schema)
+}
+
+func (p TypeBoxPrinter) primitive(s *Primitive, options *jsonObject) string {
+	// the keywords are the same as in JSON Schema, apart from the type which
+	// is the function that builds the schema.
+	keywords := &jsonObject{}
+	jsonSchemaBuilder{}.primitive(keywords, s)
+
+	if _, ok := keywords.values["enum"]; ok {
+		return typeBoxCall("Union", options, `[Type.Literal("true"), Type.Literal("false")]`)
+	}
+	if keywords.values["const"] == true {
+		return typeBoxLiteral("true", options)
+	}
+
+	fn := "Unknown"
+	switch keywords.values["type"] {
+	case "string":
+		fn = "String"
+	case "number":
+		fn = "Number"
+	case "integer":
+		fn = "Integer"
+	case "boolean":
+		fn = "Boolean"
+	default:
+		if s.Kind == PrimitiveAny {
+			fn = "Any"
+		}
+	}
+
+	merged := &jsonObject{}
+	for _, key := range keywords.keys {
+		if key != "type" {
+			merged.set(key, keywords.values[key])
+		}
+	}
+	if options != nil {
+		for _, key := range options.keys {
+			merged.set(key, options.values[key])
+		}
+	}
+	return typeBoxCall(fn, merged)
+}
+
+// Type prints the TypeScript type of the JSON a schema accepts, which is
+// written out by hand for declarations that refer to themselves.
+func (p TypeBoxPrinter) Type(s Schema, indent int) string {
+	return typePrinter{prefix: p.Prefix, argType: "TSchema & { static: %s }"}.typ(s, indent)
+}
+
+// writes out the schema of a declaration that is not declared yet in place of
+// a reference to it. Where it leads back to itself, it is wrapped in a
+// Type.Recursive of its own with a parameter named after it.
+func (p TypeBoxPrinter) inline(d *Declaration, indent int, options *jsonObject) string {
+	inner := p
+	inner.inlining = append(append([]string{}, p.inlining...), d.Key)
+	if !inner.reaches(d.Schema, d.Key, map[string]bool{}) {
+		return inner.schema(d.Schema, indent, options)
+	}
+	return typeBoxCall("Recursive", options,
+		fmt.Sprintf("(This%s) => %s", p.Prefix+d.Name, inner.schema(d.Schema, indent, nil)))
+}
+
+// reports whether a schema written out in place leads to the declaration with
+// the key, other than through the declarations that already have a parameter
+// to refer to them with.
+func (p TypeBoxPrinter) reaches(s Schema, key string, seen map[string]bool) bool {
+	found := false
+	walkSchema(s, func(s Schema) {
+		ref, ok := s.(*Reference)
+		if !ok || found {
+			return
+		}
+		switch {
+		case ref.Key == key:
+			found = true
+		case ref.Key == p.self || p.isInlining(ref.Key) || seen[ref.Key]:
+		default:
+			if target := p.cycle[ref.Key]; target != nil && len(target.Params) == 0 {
+				seen[ref.Key] = true
+				found = p.reaches(target.Schema, key, seen)
+			}
+		}
+	})
+	return found
+}
+
+// reports whether the schema of a later declaration is being written out in
+// place, in which case it is referred to by its parameter.
+func (p TypeBoxPrinter) isInlining(key string) bool {
+	for _, k := range p.inlining {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// checks whether a declaration refers to itself directly, rather than only
+// through other declarations.
+func refersToSelf(d *Declaration) bool {
+	found := false
+	walkSchema(d.Schema, func(s Schema) {
+		if r, ok := s.(*Reference); ok && r.Lazy && r.Key == d.Key {
+			found = true
+		}
+	})
+	return found
+}
+
+// calls a Type function with the arguments, followed by the options if there
+// are any.
+func typeBoxCall(fn string, options *jsonObject, args ...string) string {
+	if o := jsObject(options); o != "" {
+		args = append(args, o)
+	}
+	return fmt.Sprintf("Type.%s(%s)", fn, strings.Join(args, ", "))
+}
+
+// adds options to a schema that is not built here, such as a reference to
+// another declaration, by spreading it into a new one.
+func typeBoxSpread(schema string, options *jsonObject) string {
+	o := jsObject(options)
+	if o == "" {
+		return schema
+	}
+	return fmt.Sprintf("{ ...%s, %s", schema, strings.TrimPrefix(o, "{ "))
+}
+
+func typeBoxLiteral(value string, options *jsonObject) string {
+	if value == "null" {
+		return typeBoxCall("Null", options)
+	}
+	return typeBoxCall("Literal", options, value)
+}
+
+// copies the object, or makes an empty one if there is none, so that keys can
+// be added without changing the original.
+func (o *jsonObject) copyOrEmpty() *jsonObject {
+	if o == nil {
+		return &jsonObject{}
+	}
+	return o.copy()
+}
+
+// prints the object as a JavaScript object literal on one line, or nothing if
+// it is empty. json.RawMessage values are written as they are.
+func jsObject(o *jsonObject) string {
+	if o == nil || len(o.keys) == 0 {
+		return ""
+	}
+	entries := make([]string, len(o.keys))
+	for i, key := range o.keys {
+		entries[i] = fmt.Sprintf("%s: %s", key, jsValue(o.values[key]))
+	}
+	return fmt.Sprintf("{ %s }", strings.Join(entries, ", "))
+}
+
+func jsValue(v interface{}) string {
+	switch v := v.(type) {
+	case *jsonObject:
+		if len(v.keys) == 0 {
+			return "{}"
+		}
+		return jsObject(v)
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = jsValue(item)
+		}
+		return fmt.Sprintf("[%s]", strings.Join(items, ", "))
+	case json.RawMessage:
+		return string(v)
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,100 @@
+package supervillain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Country struct {
+	Name    string
+	Capital *City
+}
+
+type Region struct {
+	Country *Country
+	Cities  []City
+}
+
+type City struct {
+	Region *Region
+	Twin   *City
+}
+
+// the schema declared first writes out the ones after it in place, so that no
+// schema refers to another with Type.Ref.
+func TestConvertTypeBoxMutuallyRecursive(t *testing.T) {
+	c := NewConverter(nil)
+	assert.Equal(t, `export type Employee = {
+  Name: string
+  Department: Department | null
+}
+export const EmployeeSchema = Type.Recursive((This) => Type.Object({
+  Name: Type.String(),
+  Department: Type.Union([Type.Object({
+    Title: Type.String(),
+    Manager: This,
+    Staff: Type.Union([Type.Array(This), Type.Null()]),
+  }), Type.Null()]),
+}), { $id: "Employee" })
+
+export type Department = {
+  Title: string
+  Manager: Employee
+  Staff: Employee[] | null
+}
+export const DepartmentSchema = Type.Object({
+  Title: Type.String(),
+  Manager: EmployeeSchema,
+  Staff: Type.Union([Type.Array(EmployeeSchema), Type.Null()]),
+}, { $id: "Department" })
+
+export const OrganisationSchema = Type.Object({
+  Departments: Type.Union([Type.Array(DepartmentSchema), Type.Null()]),
+  CEO: EmployeeSchema,
+})
+export type Organisation = Static<typeof OrganisationSchema>
+
+`, c.ConvertLibrary(TypeBoxPrinter{}, []interface{}{Organisation{}}))
+}
+
+func TestConvertTypeBoxNestedCycles(t *testing.T) {
+	c := NewConverter(nil)
+	assert.Equal(t, `export type Region = {
+  Country: Country | null
+  Cities: City[] | null
+}
+export const RegionSchema = Type.Recursive((This) => Type.Object({
+  Country: Type.Union([Type.Object({
+    Name: Type.String(),
+    Capital: Type.Union([Type.Recursive((ThisCity) => Type.Object({
+      Region: Type.Union([This, Type.Null()]),
+      Twin: Type.Union([ThisCity, Type.Null()]),
+    })), Type.Null()]),
+  }), Type.Null()]),
+  Cities: Type.Union([Type.Array(Type.Recursive((ThisCity) => Type.Object({
+    Region: Type.Union([This, Type.Null()]),
+    Twin: Type.Union([ThisCity, Type.Null()]),
+  }))), Type.Null()]),
+}), { $id: "Region" })
+
+export type City = {
+  Region: Region | null
+  Twin: City | null
+}
+export const CitySchema = Type.Recursive((This) => Type.Object({
+  Region: Type.Union([RegionSchema, Type.Null()]),
+  Twin: Type.Union([This, Type.Null()]),
+}), { $id: "City" })
+
+export type Country = {
+  Name: string
+  Capital: City | null
+}
+export const CountrySchema = Type.Object({
+  Name: Type.String(),
+  Capital: Type.Union([CitySchema, Type.Null()]),
+}, { $id: "Country" })
+
+`, c.ConvertLibrary(TypeBoxPrinter{}, []interface{}{Country{}}))
+}
@@ -0,0 +1,196 @@
+package supervillain
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ConvertTypeScript converts the inputs like ConvertSlice, but to plain
+// TypeScript declarations that do not need Zod. Structs are declared as
+// interfaces and everything else as types, with the same names and in the same
+// order as the schemas.
+func (c *Converter) ConvertTypeScript(inputs []interface{}) string {
+	for _, input := range inputs {
+		c.convertTopLevel(reflect.TypeOf(input))
+	}
+
+	output := strings.Builder{}
+	if c.preamble != nil {
+		output.WriteString(c.preamble.header(sources(c.outputs)))
+	}
+	printer := TypeScriptPrinter{Prefix: c.prefix}
+	for _, d := range c.declarations(c.outputs) {
+		output.WriteString(printer.Declaration(d))
+		output.WriteString("\n\n")
+	}
+	return output.String()
+}
+
+// ConvertTypeScriptE is like ConvertTypeScript but returns every problem found
+// in the types as a ConversionErrors instead of panicking on the first one.
+func (c *Converter) ConvertTypeScriptE(inputs []interface{}) (string, error) {
+	return c.collectErrors(func() string {
+		return c.ConvertTypeScript(inputs)
+	})
+}
+
+// TypeScriptPrinter prints declarations as plain TypeScript types. The types
+// describe the JSON that encoding/json writes, so fields with the `,string`
+// option are strings even when they are coerced by Zod.
+type TypeScriptPrinter struct {
+	// Prefix is added to the start of every declared name.
+	Prefix string
+}
+
+// Declaration prints a declaration as an interface if it is an object and as a
+// type otherwise, along with the object of values of an enum.
+func (p TypeScriptPrinter) Declaration(d *Declaration) string {
+	name := p.Prefix + d.Name
+	if len(d.Params) > 0 {
+		name = fmt.Sprintf("%s<%s>", name, strings.Join(d.Params, ", "))
+	}
+	jsdoc, _ := formatDoc(d.Doc, 0)
+
+	output := strings.Builder{}
+	output.WriteString(jsdoc)
+	if _, ok := d.Schema.(*Object); ok {
+		output.WriteString(fmt.Sprintf("export interface %s %s", name, p.Type(d.Schema, 0)))
+	} else {
+		output.WriteString(fmt.Sprintf("export type %s = %s", name, p.Type(d.Schema, 0)))
+	}
+	output.WriteString(enumObject(p.Prefix+d.Name, d.Schema))
+
+	return output.String()
+}
+
+// Type prints the TypeScript type of a schema. Objects are indented by the
+// given level, as they would be when written inside other objects at it.
+func (p TypeScriptPrinter) Type(s Schema, indent int) string {
+	return typePrinter{prefix: p.Prefix}.typ(s, indent)
+}
+
+// prints TypeScript types, either of the JSON or of the values Zod parses it
+// to, which differ where Zod coerces values and for generic types, which take
+// schemas as their type arguments.
+type typePrinter struct {
+	prefix string
+	parsed bool
+	// the type that the type arguments of generic types are wrapped in, such
+	// as `z.ZodType<%s>`, for generic types that take schemas.
+	argType string
+	// whether properties and arrays are readonly, as Effect infers them.
+	readonly bool
+	// names to use instead of the declared ones for references, by key.
+	names map[string]string
+}
+
+func (p typePrinter) typ(s Schema, indent int) string {
+	switch s := s.(type) {
+	case *Primitive:
+		if s.Coerce && !p.parsed {
+			if s.Kind == PrimitiveBoolean {
+				return `"true" | "false"`
+			}
+			return "string"
+		}
+		switch s.Kind {
+		case PrimitiveString:
+			return "string"
+		case PrimitiveNumber:
+			return "number"
+		case PrimitiveBoolean:
+			return "boolean"
+		case PrimitiveBigInt:
+			return "bigint"
+		case PrimitiveAny:
+			return "any"
+		}
+		return "unknown"
+
+	case *Const:
+		return s.Value
+
+	case *Enum:
+		return strings.Join(s.Values, " | ")
+
+	case *Object:
+		output := strings.Builder{}
+		output.WriteString("{\n")
+		for _, f := range s.Fields {
+			jsdoc, _ := formatDoc(f.Doc, indent+1)
+			output.WriteString(jsdoc)
+			output.WriteString(indentation(indent + 1))
+			if p.readonly {
+				output.WriteString("readonly ")
+			}
+			output.WriteString(f.Name)
+			if f.Optional {
+				output.WriteString("?")
+			}
+			output.WriteString(": ")
+			output.WriteString(p.typ(f.Schema, indent+1))
+			if f.Nullable {
+				output.WriteString(" | null")
+			}
+			output.WriteString("\n")
+		}
+		output.WriteString(indentation(indent))
+		output.WriteString("}")
+		return output.String()
+
+	case *Array:
+		if p.readonly {
+			return fmt.Sprintf("ReadonlyArray<%s>", p.typ(s.Items, indent))
+		}
+		return arrayType(p.typ(s.Items, indent))
+
+	case *Tuple:
+		items := make([]string, len(s.Items))
+		for i, item := range s.Items {
+			items[i] = p.typ(item, indent)
+		}
+		if p.readonly {
+			return fmt.Sprintf("readonly [%s]", strings.Join(items, ", "))
+		}
+		return fmt.Sprintf("[%s]", strings.Join(items, ", "))
+
+	case *Record:
+		if p.readonly {
+			return fmt.Sprintf("Readonly<Record<%s, %s>>", p.typ(s.Key, indent), p.typ(s.Value, indent))
+		}
+		return fmt.Sprintf("Record<%s, %s>", p.typ(s.Key, indent), p.typ(s.Value, indent))
+
+	case *Union:
+		members := make([]string, len(s.Members))
+		for i, m := range s.Members {
+			members[i] = p.typ(m, indent)
+		}
+		return strings.Join(members, " | ")
+
+	case *OrEmpty:
+		return fmt.Sprintf("%s | %s", p.typ(s.Schema, indent), s.Value)
+
+	case *Reference:
+		if name, ok := p.names[s.Key]; ok {
+			return name
+		}
+		if len(s.Args) == 0 {
+			return p.prefix + s.Name
+		}
+		args := make([]string, len(s.Args))
+		for i, arg := range s.Args {
+			args[i] = p.typ(arg, indent)
+			if p.argType != "" {
+				args[i] = fmt.Sprintf(p.argType, args[i])
+			}
+		}
+		return fmt.Sprintf("%s%s<%s>", p.prefix, s.Name, strings.Join(args, ", "))
+
+	case *Param:
+		return s.Name
+	}
+
+	// custom schemas are opaque strings so their type is not known here.
+	return "unknown"
+}
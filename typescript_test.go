@@ -0,0 +1,87 @@
+package supervillain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertTypeScript(t *testing.T) {
+	c := NewConverter(nil)
+	assert.Equal(t, `export interface Page<T> {
+  Items: T[] | null
+  Next: string
+}
+
+export interface Parcel {
+  sku: string
+  quantity: number
+  price: unknown
+  notes: string | null
+  tags?: string[]
+}
+
+export interface Shipment {
+  parcels: Parcel[] | null
+  coupons: Record<string, number> | null
+  box: Page<Parcel>
+  parent?: Shipment
+}
+
+`, c.ConvertTypeScript([]interface{}{Shipment{}}))
+}
+
+func TestConvertTypeScriptUnionsAndEnums(t *testing.T) {
+	c := NewConverter(nil)
+	require.NoError(t, c.RegisterUnion((*Event)(nil), "type", Created{Type: "created"}, &Deleted{Type: "deleted"}))
+	require.NoError(t, c.RegisterEnumValues(
+		EnumValue{Name: "PriorityLow", Value: PriorityLow},
+		EnumValue{Name: "PriorityHigh", Value: PriorityHigh},
+	))
+
+	assert.Equal(t, `export interface Created {
+  type: "created"
+  id: string
+}
+
+export interface Deleted {
+  type: "deleted"
+  id: string
+  reason?: string
+}
+
+export type Event = Created | Deleted
+
+export type Priority = 0 | 2
+export const Priority = {
+  Low: 0,
+  High: 2,
+} as const
+
+export interface Timeline {
+  latest: Event | null
+  priority: Priority
+  state?: unknown
+}
+
+`, c.ConvertTypeScript([]interface{}{Timeline{}}))
+}
+
+func TestConvertTypeScriptQuoted(t *testing.T) {
+	type Counter struct {
+		Count   int  `json:"count,string"`
+		Enabled bool `json:"enabled,string"`
+	}
+
+	// the types describe the JSON, before Zod would coerce it.
+	c := NewConverter(nil, WithQuotedMode(QuotedCoerce), WithPreamble(Preamble{TSNoCheck: true}))
+	assert.Equal(t, `// @ts-nocheck
+
+export interface Counter {
+  count: string
+  enabled: "true" | "false"
+}
+
+`, c.ConvertTypeScript([]interface{}{Counter{}}))
+}
@@ -0,0 +1,192 @@
+package supervillain
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// ErrInvalidUnion is reported by RegisterUnion for interfaces and members that
+// cannot be converted to a union.
+const ErrInvalidUnion ErrorCode = "invalid_union"
+
+type union struct {
+	typ reflect.Type
+	// the JSON name of the field that tells the members apart, if any.
+	discriminator string
+	members       []reflect.Type
+}
+
+// the field of a union member that is fixed to a literal value.
+type discriminatorField struct {
+	field   string
+	literal string
+}
+
+// RegisterUnion registers the concrete types that an interface type can hold,
+// so that wherever the interface is found it is converted to a union of their
+// schemas instead of `z.any()`. iface is a nil pointer to the interface, such
+// as `(*Event)(nil)`, and each member is a struct that implements it.
+//
+// When discriminator is the JSON name of a field that every member has, the
+// union is a `z.discriminatedUnion` and that field of each member is converted
+// to a literal of its value in the given member, so members must be registered
+// with the discriminator set, such as `Created{Type: "created"}`. Without a
+// discriminator the union is a plain `z.union`.
+func (c *Converter) RegisterUnion(iface any, discriminator string, members ...any) error {
+	t := reflect.TypeOf(iface)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Interface {
+		return &ConversionError{Type: t, Code: ErrInvalidUnion, Message: fmt.Sprintf("expected a pointer to an interface, got %v", t)}
+	}
+	t = t.Elem()
+	if t.Name() == "" || t.PkgPath() == "" {
+		return &ConversionError{Type: t, Code: ErrInvalidUnion, Message: fmt.Sprintf("unions must be of a declared interface type, got %v", t)}
+	}
+	if len(members) == 0 {
+		return &ConversionError{Path: t.Name(), Type: t, Code: ErrInvalidUnion, Message: "no members given"}
+	}
+
+	u := union{typ: t, discriminator: discriminator}
+	literals := map[reflect.Type]string{}
+	seen := map[string]reflect.Type{}
+
+	for _, m := range members {
+		mt := reflect.TypeOf(m)
+		if mt == nil || !mt.Implements(t) {
+			return &ConversionError{Path: t.Name(), Type: mt, Code: ErrInvalidUnion, Message: fmt.Sprintf("%v does not implement %s", mt, t)}
+		}
+		for mt.Kind() == reflect.Ptr {
+			mt = mt.Elem()
+		}
+		if mt.Kind() != reflect.Struct || mt.Name() == "" {
+			return &ConversionError{Path: t.Name(), Type: mt, Code: ErrInvalidUnion, Message: fmt.Sprintf("union members must be declared structs, got %v", mt)}
+		}
+		u.members = append(u.members, mt)
+
+		if discriminator == "" {
+			continue
+		}
+
+		literal, err := discriminatorLiteral(m, mt, discriminator)
+		if err != "" {
+			return &ConversionError{Path: t.Name(), Type: mt, Code: ErrInvalidUnion, Message: err}
+		}
+		if other, ok := seen[literal]; ok {
+			return &ConversionError{Path: t.Name(), Type: mt, Code: ErrInvalidUnion, Message: fmt.Sprintf("%s and %s both have %s %s", other, mt, discriminator, literal)}
+		}
+		seen[literal] = mt
+		literals[mt] = literal
+	}
+
+	if c.unions == nil {
+		c.unions = map[string]union{}
+	}
+	if c.discriminators == nil {
+		c.discriminators = map[reflect.Type]discriminatorField{}
+	}
+	c.unions[typeKey(t)] = u
+	for mt, literal := range literals {
+		c.discriminators[mt] = discriminatorField{field: discriminator, literal: literal}
+	}
+
+	return nil
+}
+
+// gets the JSON literal of the discriminator field in a member value, or a
+// description of why it cannot be used.
+func discriminatorLiteral(m any, mt reflect.Type, name string) (string, string) {
+	found := false
+	for _, f := range structFields(mt) {
+		if f.name == name {
+			found = true
+		}
+	}
+	if !found {
+		return "", fmt.Sprintf("%s has no field %s", mt, name)
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", fmt.Sprintf("cannot marshal %s: %v", mt, err)
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return "", fmt.Sprintf("cannot unmarshal %s: %v", b, err)
+	}
+
+	raw, ok := fields[name]
+	if !ok {
+		return "", fmt.Sprintf("%s is missing from %s", name, b)
+	}
+
+	var literal any
+	if err := json.Unmarshal(raw, &literal); err != nil {
+		return "", fmt.Sprintf("cannot unmarshal %s: %v", raw, err)
+	}
+	switch literal.(type) {
+	case string, float64, bool:
+	default:
+		return "", fmt.Sprintf("%s of %s is %s, which is not a literal", name, mt, raw)
+	}
+
+	return string(raw), ""
+}
+
+func (c *Converter) unionFor(t reflect.Type) (union, bool) {
+	if t.Kind() != reflect.Interface || t.Name() == "" {
+		return union{}, false
+	}
+	u, ok := c.unions[typeKey(t)]
+	return u, ok && u.typ == t
+}
+
+func (c *Converter) isUnion(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	_, ok := c.unionFor(t)
+	return ok
+}
+
+// gets the literal that a field of a union member is fixed to, if any.
+func (c *Converter) discriminatorFor(structType reflect.Type, field string) string {
+	d, ok := c.discriminators[structType]
+	if !ok || d.field != field {
+		return ""
+	}
+	return d.literal
+}
+
+// converts a registered interface to a reference to its union schema,
+// declaring the schema and every member if this is the first time the
+// interface is found.
+func (c *Converter) convertUnion(t reflect.Type, u union) Schema {
+	key := typeKey(t)
+	name := c.nameFor(t)
+
+	if c.isCycle(key) {
+		c.addReference(key, referenceSchema)
+		return &Reference{Key: key, Name: name, Lazy: true}
+	}
+
+	if _, ok := c.outputs[key]; !ok {
+		c.push(key)
+		members := make([]Schema, len(u.members))
+		for i, m := range u.members {
+			members[i] = c.convertSchema(m, m.Name(), 0)
+		}
+		c.pop()
+
+		recursive := c.recursive[key]
+		for _, m := range u.members {
+			if c.recursive[typeKey(m)] {
+				recursive = true
+			}
+		}
+
+		c.addSchema(c.declare(t, key, name, &Union{Members: members, Discriminator: u.discriminator}, recursive))
+	}
+
+	c.addDependency(key)
+	return &Reference{Key: key, Name: name}
+}
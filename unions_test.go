@@ -0,0 +1,187 @@
+package supervillain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Event interface {
+	isEvent()
+}
+
+type Created struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+func (Created) isEvent() {}
+
+type Deleted struct {
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func (*Deleted) isEvent() {}
+
+type Feed struct {
+	Events []Event `json:"events"`
+	Latest Event   `json:"latest"`
+	Pinned Event   `json:"pinned,omitempty"`
+}
+
+func TestRegisterUnionDiscriminated(t *testing.T) {
+	c := NewConverter(nil)
+	require.NoError(t, c.RegisterUnion((*Event)(nil), "type", Created{Type: "created"}, &Deleted{Type: "deleted"}))
+
+	assert.Equal(t,
+		`export const CreatedSchema = z.object({
+  type: z.literal("created"),
+  id: z.string(),
+})
+export type Created = z.infer<typeof CreatedSchema>
+
+export const DeletedSchema = z.object({
+  type: z.literal("deleted"),
+  id: z.string(),
+  reason: z.string().optional(),
+})
+export type Deleted = z.infer<typeof DeletedSchema>
+
+export const EventSchema = z.discriminatedUnion("type", [CreatedSchema, DeletedSchema])
+export type Event = z.infer<typeof EventSchema>
+
+export const FeedSchema = z.object({
+  events: EventSchema.array().nullable(),
+  latest: EventSchema.nullable(),
+  pinned: EventSchema.optional(),
+})
+export type Feed = z.infer<typeof FeedSchema>
+
+`,
+		c.Convert(Feed{}))
+}
+
+func TestRegisterUnionPlain(t *testing.T) {
+	c := NewConverter(nil)
+	require.NoError(t, c.RegisterUnion((*Event)(nil), "", Created{}, &Deleted{}))
+
+	assert.Equal(t,
+		`export const CreatedSchema = z.object({
+  type: z.string(),
+  id: z.string(),
+})
+export type Created = z.infer<typeof CreatedSchema>
+
+export const DeletedSchema = z.object({
+  type: z.string(),
+  id: z.string(),
+  reason: z.string().optional(),
+})
+export type Deleted = z.infer<typeof DeletedSchema>
+
+export const EventSchema = z.union([CreatedSchema, DeletedSchema])
+export type Event = z.infer<typeof EventSchema>
+
+export const FeedSchema = z.object({
+  events: EventSchema.array().nullable(),
+  latest: EventSchema.nullable(),
+  pinned: EventSchema.optional(),
+})
+export type Feed = z.infer<typeof FeedSchema>
+
+`,
+		c.Convert(Feed{}))
+}
+
+func TestUnregisteredInterface(t *testing.T) {
+	assert.Equal(t,
+		`export const FeedSchema = z.object({
+  events: z.any().array().nullable(),
+  latest: z.any(),
+  pinned: z.any(),
+})
+export type Feed = z.infer<typeof FeedSchema>
+
+`,
+		StructToZodSchema(Feed{}))
+}
+
+type Expr interface {
+	isExpr()
+}
+
+type Literal struct {
+	Kind  string  `json:"kind"`
+	Value float64 `json:"value"`
+}
+
+func (Literal) isExpr() {}
+
+type Sum struct {
+	Kind  string `json:"kind"`
+	Left  Expr   `json:"left"`
+	Right Expr   `json:"right"`
+}
+
+func (Sum) isExpr() {}
+
+type Formula struct {
+	Root Expr `json:"root"`
+}
+
+func TestRegisterUnionRecursive(t *testing.T) {
+	c := NewConverter(nil)
+	require.NoError(t, c.RegisterUnion((*Expr)(nil), "kind", Literal{Kind: "literal"}, Sum{Kind: "sum"}))
+
+	assert.Equal(t,
+		`export const LiteralSchema = z.object({
+  kind: z.literal("literal"),
+  value: z.number(),
+})
+export type Literal = z.infer<typeof LiteralSchema>
+
+export type Sum = {
+  kind: "sum"
+  left: Expr | null
+  right: Expr | null
+}
+export const SumSchema: z.ZodType<Sum> = z.object({
+  kind: z.literal("sum"),
+  left: z.lazy(() => ExprSchema).nullable(),
+  right: z.lazy(() => ExprSchema).nullable(),
+})
+
+export type Expr = Literal | Sum
+export const ExprSchema: z.ZodType<Expr> = z.union([LiteralSchema, SumSchema])
+
+export const FormulaSchema = z.object({
+  root: ExprSchema.nullable(),
+})
+export type Formula = z.infer<typeof FormulaSchema>
+
+`,
+		c.Convert(Formula{}))
+}
+
+func TestRegisterUnionInvalid(t *testing.T) {
+	c := NewConverter(nil)
+
+	cases := map[string]error{
+		"expected a pointer to an interface, got supervillain.Created":  c.RegisterUnion(Created{}, "type", Created{}),
+		"unions must be of a declared interface type, got interface {}": c.RegisterUnion((*any)(nil), "type", Created{}),
+		"no members given": c.RegisterUnion((*Event)(nil), "type"),
+		"supervillain.Deleted does not implement supervillain.Event":        c.RegisterUnion((*Event)(nil), "type", Deleted{}),
+		"supervillain.Created has no field kind":                            c.RegisterUnion((*Event)(nil), "kind", Created{}),
+		"supervillain.Created and supervillain.Deleted both have type \"\"": c.RegisterUnion((*Event)(nil), "type", Created{}, &Deleted{}),
+	}
+	for message, err := range cases {
+		var cerr *ConversionError
+		require.True(t, errors.As(err, &cerr), message)
+		assert.Equal(t, ErrInvalidUnion, cerr.Code)
+		assert.Equal(t, message, cerr.Message)
+	}
+}
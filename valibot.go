@@ -0,0 +1,326 @@
+package supervillain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValibotPrinter prints declarations as Valibot schemas and the TypeScript
+// types that are inferred from them.
+//
+// Zod methods from the `zod` tag have no Valibot equivalent so they are left
+// out, and custom schemas from the tag are `v.unknown()`.
+type ValibotPrinter struct {
+	// Prefix is added to the start of every declared name.
+	Prefix string
+}
+
+// Name is "valibot".
+func (p ValibotPrinter) Name() string {
+	return "valibot"
+}
+
+// Import imports valibot as v.
+func (p ValibotPrinter) Import(Preamble) string {
+	return "import * as v from \"valibot\"\n"
+}
+
+// Declaration prints the schema and type of a declaration, along with the
+// object of values of an enum.
+func (p ValibotPrinter) Declaration(d *Declaration) string {
+	name := p.Prefix + d.Name
+	schemaName := schemaName(p.Prefix, d.Name)
+	jsdoc, _ := formatDoc(d.Doc, 0)
+
+	schema := d.Schema
+	if u, ok := schema.(*Union); ok && d.Recursive {
+		// v.variant only accepts object schemas, not members annotated with
+		// v.GenericSchema, so it falls back to v.union.
+		plain := *u
+		plain.Discriminator = ""
+		schema = &plain
+	}
+	valibot := p.Schema(schema, 0)
+	if d.Description != "" {
+		valibot = valibotPipe(valibot, valibotDescription(d.Description))
+	}
+
+	output := strings.Builder{}
+	switch {
+	case len(d.Params) > 0:
+		typeParams := make([]string, len(d.Params))
+		valueParams := make([]string, len(d.Params))
+		for i, param := range d.Params {
+			typeParams[i] = fmt.Sprintf("%s extends v.GenericSchema", param)
+			valueParams[i] = fmt.Sprintf("%s: %s", strings.ToLower(param), param)
+		}
+		output.WriteString(fmt.Sprintf("export const %s = <%s>(%s) => %s\n",
+			schemaName, strings.Join(typeParams, ", "), strings.Join(valueParams, ", "), valibot))
+		output.WriteString(jsdoc)
+		output.WriteString(fmt.Sprintf("export type %s<%s> = v.InferOutput<ReturnType<typeof %s<%s>>>",
+			name, strings.Join(typeParams, ", "), schemaName, strings.Join(d.Params, ", ")))
+
+	case d.Recursive:
+		// v.InferOutput cannot be used for types that refer to themselves so
+		// the type is written out by hand and the schema is annotated with it.
+		output.WriteString(jsdoc)
+		output.WriteString(fmt.Sprintf("export type %s = %s\n", name, p.Type(d.Schema, 0)))
+		output.WriteString(fmt.Sprintf("export const %s: v.GenericSchema<%s> = %s", schemaName, name, valibot))
+
+	default:
+		output.WriteString(fmt.Sprintf("export const %s = %s\n", schemaName, valibot))
+		output.WriteString(jsdoc)
+		output.WriteString(fmt.Sprintf("export type %s = v.InferOutput<typeof %s>", name, schemaName))
+	}
+
+	output.WriteString(enumObject(name, d.Schema))
+
+	return output.String()
+}
+
+// Schema prints a schema as a Valibot expression. Objects are indented by the
+// given level, as they would be when written inside other objects at it.
+func (p ValibotPrinter) Schema(s Schema, indent int) string {
+	switch s := s.(type) {
+	case *Primitive:
+		return p.primitive(s)
+
+	case *Const:
+		return valibotLiteral(s.Value)
+
+	case *Enum:
+		strs := true
+		for _, v := range s.Values {
+			if !strings.HasPrefix(v, `"`) {
+				strs = false
+			}
+		}
+		if strs {
+			return fmt.Sprintf("v.picklist([%s])", strings.Join(s.Values, ", "))
+		}
+
+		literals := make([]string, len(s.Values))
+		for i, v := range s.Values {
+			literals[i] = valibotLiteral(v)
+		}
+		if len(literals) == 1 {
+			return literals[0]
+		}
+		return fmt.Sprintf("v.union([%s])", strings.Join(literals, ", "))
+
+	case *Object:
+		output := strings.Builder{}
+		output.WriteString("v.object({\n")
+		for _, f := range s.Fields {
+			output.WriteString(p.field(f, indent+1))
+		}
+		output.WriteString(indentation(indent))
+		output.WriteString("})")
+		return output.String()
+
+	case *Array:
+		return valibotPipe(fmt.Sprintf("v.array(%s)", p.Schema(s.Items, indent)), p.checks(s.Checks, "Length", "")...)
+
+	case *Tuple:
+		items := make([]string, len(s.Items))
+		for i, item := range s.Items {
+			items[i] = p.Schema(item, indent)
+		}
+		return fmt.Sprintf("v.tuple([%s])", strings.Join(items, ", "))
+
+	case *Record:
+		return fmt.Sprintf("v.record(%s, %s)", p.Schema(s.Key, indent), p.Schema(s.Value, indent))
+
+	case *Union:
+		members := make([]string, len(s.Members))
+		for i, m := range s.Members {
+			members[i] = p.Schema(m, indent)
+		}
+		switch {
+		case s.Discriminator != "":
+			return fmt.Sprintf("v.variant(%s, [%s])", jsString(s.Discriminator), strings.Join(members, ", "))
+		case len(members) == 1:
+			return members[0]
+		}
+		return fmt.Sprintf("v.union([%s])", strings.Join(members, ", "))
+
+	case *OrEmpty:
+		return fmt.Sprintf("v.union([%s, %s])", p.Schema(s.Schema, indent), valibotLiteral(s.Value))
+
+	case *Reference:
+		name := schemaName(p.Prefix, s.Name)
+		if s.Lazy {
+			return fmt.Sprintf("v.lazy(() => %s)", name)
+		}
+		if len(s.Args) > 0 {
+			args := make([]string, len(s.Args))
+			for i, arg := range s.Args {
+				args[i] = p.Schema(arg, indent)
+			}
+			return fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+		}
+		return name
+
+	case *Param:
+		return strings.ToLower(s.Name)
+
+	case *Custom:
+		if schema, ok := s.Libraries[p.Name()]; ok {
+			return schema
+		}
+	}
+
+	return "v.unknown()"
+}
+
+func (p ValibotPrinter) field(f *Field, indent int) string {
+	jsdoc, _ := formatDoc(f.Doc, indent)
+
+	schema := p.Schema(f.Schema, indent)
+	if f.Description != "" {
+		schema = valibotPipe(schema, valibotDescription(f.Description))
+	}
+
+	// the default is the second argument of v.optional and v.nullish, so a
+	// field with one is always wrapped in either.
+	def := ""
+	if f.Default != "" {
+		def = ", " + f.Default
+	}
+	switch {
+	case f.Nullish:
+		schema = fmt.Sprintf("v.nullish(%s%s)", schema, def)
+	case f.Nullable:
+		schema = fmt.Sprintf("v.nullable(%s)", schema)
+		if f.Optional || def != "" {
+			schema = fmt.Sprintf("v.optional(%s%s)", schema, def)
+		}
+	case f.Optional || def != "":
+		schema = fmt.Sprintf("v.optional(%s%s)", schema, def)
+	}
+
+	return fmt.Sprintf("%s%s%s: %s,\n", jsdoc, indentation(indent), f.Name, schema)
+}
+
+func (p ValibotPrinter) primitive(s *Primitive) string {
+	if s.Kind == PrimitiveBoolean && s.Coerce {
+		return `v.pipe(v.picklist(["true", "false"]), v.transform((s) => s === "true"))`
+	}
+
+	schema := ""
+	bound, suffix := "Value", ""
+	switch s.Kind {
+	case PrimitiveString:
+		schema = "v.string()"
+		bound = "Length"
+	case PrimitiveNumber:
+		schema = "v.number()"
+	case PrimitiveBoolean:
+		schema = "v.boolean()"
+	case PrimitiveBigInt:
+		schema = "v.bigint()"
+		suffix = "n"
+	case PrimitiveAny:
+		schema = "v.any()"
+	default:
+		schema = "v.unknown()"
+	}
+
+	actions := p.checks(s.Checks, bound, suffix)
+	if s.Coerce && s.Kind != PrimitiveString {
+		// Valibot has no coercion, so the string is transformed into the kind
+		// before it is checked.
+		convert := "Number"
+		if s.Kind == PrimitiveBigInt {
+			convert = "BigInt"
+		}
+		actions = append([]string{fmt.Sprintf("v.transform(%s)", convert), schema}, actions...)
+		schema = "v.string()"
+	}
+
+	return valibotPipe(schema, actions...)
+}
+
+// the Valibot actions for each kind of check, with the value of the check.
+// Bounds are written with %[1]s, which is either Value or Length.
+var valibotChecks = map[CheckKind]string{
+	CheckInt:                "v.integer()",
+	CheckMin:                "v.min%[1]s(%[2]s)",
+	CheckMax:                "v.max%[1]s(%[2]s)",
+	CheckLength:             "v.length(%[2]s)",
+	CheckGreaterThan:        "v.gtValue(%[2]s)",
+	CheckGreaterThanOrEqual: "v.minValue(%[2]s)",
+	CheckLessThan:           "v.ltValue(%[2]s)",
+	CheckLessThanOrEqual:    "v.maxValue(%[2]s)",
+	CheckTrue:               "v.value(true)",
+	CheckEmail:              "v.email()",
+	CheckURL:                "v.url()",
+	CheckUUID:               "v.uuid()",
+	CheckLowercase:          "v.check((s) => s === s.toLowerCase())",
+	CheckUppercase:          "v.check((s) => s === s.toUpperCase())",
+}
+
+// prints the checks as Valibot actions. bound is whether Min and Max are of the
+// Value or the Length, and suffix is added to the values of numbers, such as
+// `n` for bigints.
+func (p ValibotPrinter) checks(checks []Check, bound, suffix string) []string {
+	actions := []string{}
+	for _, check := range checks {
+		switch check.Kind {
+		case CheckNonNegative:
+			actions = append(actions, fmt.Sprintf("v.minValue(0%s)", suffix))
+		case CheckNonZero:
+			actions = append(actions, fmt.Sprintf("v.notValue(0%s)", suffix))
+		case CheckIP:
+			actions = append(actions, fmt.Sprintf("v.ip%s()", check.Value))
+		case CheckRegex:
+			actions = append(actions, fmt.Sprintf("v.regex(/%s/)", check.Value))
+		case CheckStartsWith:
+			actions = append(actions, fmt.Sprintf("v.startsWith(%s)", jsString(check.Value)))
+		case CheckEndsWith:
+			actions = append(actions, fmt.Sprintf("v.endsWith(%s)", jsString(check.Value)))
+		case CheckIncludes:
+			actions = append(actions, fmt.Sprintf("v.includes(%s)", jsString(check.Value)))
+		case CheckGreaterThan, CheckGreaterThanOrEqual, CheckLessThan, CheckLessThanOrEqual:
+			actions = append(actions, fmt.Sprintf(valibotChecks[check.Kind], bound, check.Value+suffix))
+		case CheckMin, CheckMax:
+			value := check.Value
+			if bound == "Value" {
+				value += suffix
+			}
+			actions = append(actions, fmt.Sprintf(valibotChecks[check.Kind], bound, value))
+		default:
+			action := valibotChecks[check.Kind]
+			if strings.Contains(action, "%") {
+				action = fmt.Sprintf(action, bound, check.Value)
+			}
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// Type prints the TypeScript type of the values a schema accepts once parsed,
+// which is written out by hand for declarations that refer to themselves.
+func (p ValibotPrinter) Type(s Schema, indent int) string {
+	return typePrinter{prefix: p.Prefix, parsed: true, argType: "v.GenericSchema<%s>"}.typ(s, indent)
+}
+
+// runs the actions on the schema, if there are any.
+func valibotPipe(schema string, actions ...string) string {
+	if len(actions) == 0 {
+		return schema
+	}
+	return fmt.Sprintf("v.pipe(%s, %s)", schema, strings.Join(actions, ", "))
+}
+
+func valibotLiteral(value string) string {
+	if value == "null" {
+		return "v.null()"
+	}
+	return fmt.Sprintf("v.literal(%s)", value)
+}
+
+func valibotDescription(text string) string {
+	return fmt.Sprintf("v.description(%s)", jsString(text))
+}
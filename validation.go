@@ -0,0 +1,393 @@
+package supervillain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownValidation is reported as a diagnostic for validation rules that
+// have no equivalent in the schema, so the schema accepts more than the rule.
+const ErrUnknownValidation ErrorCode = "unknown_validation"
+
+type validationTagOption string
+
+func (v validationTagOption) apply(c *Converter) {
+	c.validationTag = string(v)
+}
+
+// WithValidationTags translates the rules in the given struct tag, usually
+// `validate` as used by go-playground/validator, into schema methods such as
+// `.min()`, `.email()` and `.uuid()`. Rules that cannot be translated are
+// reported by Diagnostics.
+func WithValidationTags(key string) Option {
+	return validationTagOption(key)
+}
+
+// Diagnostics returns the problems found so far that did not stop a type from
+// being converted, such as validation rules that could not be translated.
+func (c *Converter) Diagnostics() ConversionErrors {
+	return c.diagnostics
+}
+
+// reports a problem with the type at the current path that does not stop the
+// conversion.
+func (c *Converter) warn(t reflect.Type, code ErrorCode, message string) {
+	d := &ConversionError{
+		Path:    c.currentPath(),
+		Type:    t,
+		Code:    code,
+		Message: message,
+	}
+
+	c.diagnostics = append(c.diagnostics, d)
+}
+
+// gets the validation rules of a field, with the escaped commas and pipes that
+// validator allows in parameters restored.
+func (c *Converter) validationRules(f reflect.StructField) []string {
+	if c.validationTag == "" {
+		return nil
+	}
+	tag := f.Tag.Get(c.validationTag)
+	if tag == "" || tag == "-" {
+		return nil
+	}
+
+	rules := strings.Split(tag, ",")
+	for i, rule := range rules {
+		rules[i] = strings.NewReplacer("0x2C", ",", "0x7C", "|").Replace(rule)
+	}
+	return rules
+}
+
+// checks if a field must be present and not null according to its validation
+// rules, in which case it is neither optional nor nullable.
+func (c *Converter) isRequired(f reflect.StructField) bool {
+	for _, rule := range c.validationRules(f) {
+		if rule == "dive" {
+			break
+		}
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// converts a type with validation rules. rules after `dive` apply to the
+// elements of slices and arrays and the values of maps.
+func (c *Converter) convertValidated(t reflect.Type, rules []string, name string, indent int) Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	here, elem, dive := rules, []string(nil), false
+	for i, rule := range rules {
+		if rule == "dive" {
+			here, elem, dive = rules[:i], rules[i+1:], true
+			break
+		}
+	}
+
+	if c.isCustom(t) || c.isEnum(t) {
+		if len(rules) > 0 {
+			c.warn(t, ErrUnknownValidation, fmt.Sprintf("validation rules are not applied to custom types: %s", strings.Join(rules, ",")))
+		}
+		return c.convertSchema(t, name, indent)
+	}
+
+	if !dive {
+		return c.applyRules(t, c.convertSchema(t, name, indent), here)
+	}
+
+	var schema Schema
+	switch {
+	case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8:
+		c.pushPath("[]")
+		schema = &Array{Items: c.convertValidated(t.Elem(), elem, name, indent)}
+		c.popPath()
+
+	case t.Kind() == reflect.Array:
+		c.pushPath("[]")
+		schema = c.arraySchema(c.convertValidated(t.Elem(), elem, name, indent), t.Len())
+		c.popPath()
+
+	case t.Kind() == reflect.Map:
+		c.pushPath("[key]")
+		key := c.convertSchema(t.Key(), name, indent)
+		c.popPath()
+		c.pushPath("[value]")
+		value := c.convertValidated(t.Elem(), elem, name, indent)
+		c.popPath()
+		schema = &Record{Key: key, Value: value}
+
+	default:
+		c.warn(t, ErrUnknownValidation, fmt.Sprintf("dive is only applied to slices, arrays and maps, not %s", t.Kind()))
+		schema = c.convertSchema(t, name, indent)
+	}
+
+	return c.applyRules(t, schema, here)
+}
+
+type ruleKind int
+
+const (
+	ruleString ruleKind = iota
+	ruleNumber
+	ruleBool
+	ruleList
+	ruleOther
+	// 64 bit integers converted to strings of digits or to bigints, which
+	// are not compared like other numbers.
+	ruleDigits
+	ruleBigInt
+)
+
+func ruleKindOf(t reflect.Type) ruleKind {
+	switch {
+	case t.Kind() == reflect.String:
+		return ruleString
+	case isInteger(t) || t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return ruleNumber
+	case t.Kind() == reflect.Bool:
+		return ruleBool
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		// []byte is written as a base64 string, so its length is not known.
+		return ruleOther
+	case t.Kind() == reflect.Slice, t.Kind() == reflect.Array:
+		return ruleList
+	}
+	return ruleOther
+}
+
+// string rules that only need a check or pattern, regardless of parameters.
+var stringRules = map[string]Check{
+	"email":       {CheckEmail, ""},
+	"url":         {CheckURL, ""},
+	"http_url":    {CheckURL, ""},
+	"uri":         {CheckURL, ""},
+	"uuid":        {CheckUUID, ""},
+	"uuid3":       {CheckUUID, ""},
+	"uuid4":       {CheckUUID, ""},
+	"uuid5":       {CheckUUID, ""},
+	"ip":          {CheckIP, ""},
+	"ipv4":        {CheckIP, "v4"},
+	"ipv6":        {CheckIP, "v6"},
+	"alpha":       {CheckRegex, "^[a-zA-Z]+$"},
+	"alphanum":    {CheckRegex, "^[a-zA-Z0-9]+$"},
+	"numeric":     {CheckRegex, `^[-+]?[0-9]+(?:\.[0-9]+)?$`},
+	"number":      {CheckRegex, "^[0-9]+$"},
+	"hexadecimal": {CheckRegex, "^(0[xX])?[0-9a-fA-F]+$"},
+	"lowercase":   {CheckLowercase, ""},
+	"uppercase":   {CheckUppercase, ""},
+}
+
+// string rules that take their parameter as the value of the check.
+var stringParamRules = map[string]CheckKind{
+	"startswith": CheckStartsWith,
+	"endswith":   CheckEndsWith,
+	"contains":   CheckIncludes,
+}
+
+// number rules and their checks, for `gt=0` and friends.
+var numberRules = map[string]CheckKind{
+	"min": CheckMin,
+	"max": CheckMax,
+	"gt":  CheckGreaterThan,
+	"gte": CheckGreaterThanOrEqual,
+	"lt":  CheckLessThan,
+	"lte": CheckLessThanOrEqual,
+}
+
+// adds the checks for a list of validation rules to the schema of t.
+func (c *Converter) applyRules(t reflect.Type, schema Schema, rules []string) Schema {
+	kind := ruleKindOf(t)
+	if p, ok := schema.(*Primitive); ok && kind == ruleNumber {
+		switch p.Kind {
+		case PrimitiveString:
+			kind = ruleDigits
+		case PrimitiveBigInt:
+			kind = ruleBigInt
+		}
+	}
+	omitempty, replaced := false, false
+	checks := []Check{}
+
+	for _, rule := range rules {
+		if rule == "" {
+			continue
+		}
+		if strings.Contains(rule, "|") {
+			c.warn(t, ErrUnknownValidation, fmt.Sprintf("alternative rules are not supported: %s", rule))
+			continue
+		}
+
+		name, param, _ := strings.Cut(rule, "=")
+		unsupported := func() {
+			c.warn(t, ErrUnknownValidation, fmt.Sprintf("rule %s is not supported for %s", rule, t.Kind()))
+		}
+
+		switch {
+		case name == "omitempty":
+			omitempty = true
+
+		case name == "required":
+			// presence is handled by the field, this is about the value.
+			switch kind {
+			case ruleString:
+				checks = append(checks, Check{CheckMin, "1"})
+			case ruleNumber, ruleBigInt:
+				checks = append(checks, Check{CheckNonZero, ""})
+			case ruleDigits:
+				// the digits are already checked, so any digit besides zero
+				// makes the value nonzero.
+				checks = append(checks, Check{CheckRegex, "[1-9]"})
+			case ruleBool:
+				checks = append(checks, Check{CheckTrue, ""})
+			}
+
+		case name == "oneof":
+			values, ok := oneOf(kind, param)
+			if !ok {
+				unsupported()
+				continue
+			}
+			schema = &Enum{Values: values}
+			replaced = true
+
+		case kind == ruleNumber && hasRule(numberRules, name):
+			if _, err := strconv.ParseFloat(param, 64); err != nil {
+				unsupported()
+				continue
+			}
+			checks = append(checks, Check{numberRules[name], param})
+
+		case kind == ruleBigInt && hasRule(numberRules, name):
+			// bigints can only be compared with whole numbers.
+			if _, ok := new(big.Int).SetString(param, 10); !ok {
+				unsupported()
+				continue
+			}
+			checks = append(checks, Check{numberRules[name], param})
+
+		case kind == ruleDigits && hasRule(numberRules, name):
+			// a string of digits cannot be compared as a number, and its length
+			// is not what the rule is about.
+			c.warn(t, ErrUnknownValidation, fmt.Sprintf("rule %s is not supported for %s written as a string", rule, t.Kind()))
+
+		case (kind == ruleString || kind == ruleList) && lengthRule(name):
+			n, err := strconv.Atoi(param)
+			if err != nil || (kind == ruleList && c.arrayMode == ArrayTuple && t.Kind() == reflect.Array) {
+				unsupported()
+				continue
+			}
+			switch name {
+			case "min", "gte":
+				checks = append(checks, Check{CheckMin, strconv.Itoa(n)})
+			case "max", "lte":
+				checks = append(checks, Check{CheckMax, strconv.Itoa(n)})
+			case "gt":
+				checks = append(checks, Check{CheckMin, strconv.Itoa(n + 1)})
+			case "lt":
+				checks = append(checks, Check{CheckMax, strconv.Itoa(n - 1)})
+			case "len":
+				checks = append(checks, Check{CheckLength, strconv.Itoa(n)})
+			}
+
+		case kind == ruleString && hasRule(stringRules, name) && param == "":
+			checks = append(checks, stringRules[name])
+
+		case kind == ruleString && hasRule(stringParamRules, name):
+			checks = append(checks, Check{stringParamRules[name], param})
+
+		default:
+			unsupported()
+		}
+	}
+
+	// `oneof` replaces the schema, which may not have the same checks.
+	if replaced && len(checks) > 0 {
+		c.warn(t, ErrUnknownValidation, fmt.Sprintf("rules alongside oneof are not supported: %s", strings.Join(rules, ",")))
+	} else if !replaced && len(checks) > 0 && !addChecks(schema, checks) {
+		c.warn(t, ErrUnknownValidation, fmt.Sprintf("rules are not supported for type parameters: %s", strings.Join(rules, ",")))
+	}
+
+	// rules are skipped for empty values, which are not omitted from the JSON
+	// unless the field is also optional.
+	if omitempty {
+		switch kind {
+		case ruleString:
+			schema = &OrEmpty{Schema: schema, Value: `""`}
+		case ruleNumber:
+			schema = &OrEmpty{Schema: schema, Value: "0"}
+		case ruleDigits:
+			schema = &OrEmpty{Schema: schema, Value: `"0"`}
+		}
+	}
+
+	return schema
+}
+
+// adds checks to a schema that can have them, reporting whether it could.
+func addChecks(schema Schema, checks []Check) bool {
+	switch s := schema.(type) {
+	case *Primitive:
+		s.Checks = append(s.Checks, checks...)
+	case *Array:
+		s.Checks = append(s.Checks, checks...)
+	default:
+		return false
+	}
+	return true
+}
+
+func hasRule[V any](rules map[string]V, name string) bool {
+	_, ok := rules[name]
+	return ok
+}
+
+func lengthRule(name string) bool {
+	switch name {
+	case "min", "max", "len", "gt", "gte", "lt", "lte":
+		return true
+	}
+	return false
+}
+
+var oneOfValue = regexp.MustCompile(`'[^']*'|\S+`)
+
+// splits the values of a `oneof` rule into literals, which are separated by
+// spaces and may be quoted with single quotes.
+func oneOf(kind ruleKind, param string) ([]string, bool) {
+	values := []string{}
+	for _, v := range oneOfValue.FindAllString(param, -1) {
+		v = strings.Trim(v, "'")
+		switch kind {
+		case ruleString:
+			values = append(values, jsString(v))
+		case ruleNumber:
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				return nil, false
+			}
+			values = append(values, v)
+		case ruleDigits:
+			if _, ok := new(big.Int).SetString(v, 10); !ok {
+				return nil, false
+			}
+			values = append(values, jsString(v))
+		default:
+			return nil, false
+		}
+	}
+	return values, len(values) > 0
+}
+
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -0,0 +1,244 @@
+package supervillain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SignUp struct {
+	Username string            `json:"username" validate:"required,min=3,max=64,alphanum"`
+	Email    string            `json:"email" validate:"required,email"`
+	Website  string            `json:"website,omitempty" validate:"omitempty,url"`
+	Referral *string           `json:"referral" validate:"omitempty,uuid4"`
+	Age      uint8             `json:"age" validate:"gte=13,lt=130"`
+	Plan     string            `json:"plan" validate:"oneof=free pro 'team plus'"`
+	Seats    int               `json:"seats" validate:"oneof=1 5 10"`
+	Tags     []string          `json:"tags" validate:"required,max=5,dive,min=1,startswith=#"`
+	Scores   [3]float64        `json:"scores" validate:"dive,gt=0,lte=1"`
+	Labels   map[string]string `json:"labels" validate:"dive,max=32"`
+	Code     string            `json:"code" validate:"len=6,numeric"`
+	Accepted bool              `json:"accepted" validate:"required"`
+}
+
+func TestValidationTags(t *testing.T) {
+	c := NewConverter(nil, WithValidationTags("validate"))
+	assert.Equal(t,
+		`export const SignUpSchema = z.object({
+  username: z.string().min(1).min(3).max(64).regex(/^[a-zA-Z0-9]+$/),
+  email: z.string().min(1).email(),
+  website: z.string().url().or(z.literal("")).optional(),
+  referral: z.string().uuid().or(z.literal("")).nullable(),
+  age: z.number().int().min(0).max(255).gte(13).lt(130),
+  plan: z.enum(["free", "pro", "team plus"]),
+  seats: z.union([z.literal(1), z.literal(5), z.literal(10)]),
+  tags: z.string().min(1).startsWith("#").array().max(5),
+  scores: z.number().gt(0).lte(1).array().length(3),
+  labels: z.record(z.string(), z.string().max(32)).nullable(),
+  code: z.string().length(6).regex(/^[-+]?[0-9]+(?:\.[0-9]+)?$/),
+  accepted: z.boolean().refine((v) => v),
+})
+export type SignUp = z.infer<typeof SignUpSchema>
+
+`,
+		c.Convert(SignUp{}))
+	assert.Empty(t, c.Diagnostics())
+}
+
+func TestValidationTagsRefinementsLast(t *testing.T) {
+	type Profile struct {
+		Age    int    `json:"age" validate:"required,min=18"`
+		Handle string `json:"handle" validate:"lowercase,max=15"`
+		Code   string `json:"code" validate:"uppercase,len=4,alpha"`
+	}
+	type Ledger struct {
+		Total int64 `json:"total" validate:"required,lte=100"`
+	}
+
+	// refinements are only followed by methods that ZodEffects has too.
+	c := NewConverter(nil, WithValidationTags("validate"))
+	assert.Equal(t,
+		`export const ProfileSchema = z.object({
+  age: z.number().int().min(18).refine((v) => v !== 0),
+  handle: z.string().max(15).refine((v) => v === v.toLowerCase()),
+  code: z.string().length(4).regex(/^[a-zA-Z]+$/).refine((v) => v === v.toUpperCase()),
+})
+export type Profile = z.infer<typeof ProfileSchema>
+
+`,
+		c.Convert(Profile{}))
+
+	c = NewConverter(nil, WithValidationTags("validate"), WithInt64Mode(Int64BigInt))
+	assert.Equal(t,
+		`export const LedgerSchema = z.object({
+  total: z.bigint().min(-9223372036854775808n).max(9223372036854775807n).lte(100n).refine((v) => v !== 0n),
+})
+export type Ledger = z.infer<typeof LedgerSchema>
+
+`,
+		c.Convert(Ledger{}))
+}
+
+func TestValidationTagsDisabled(t *testing.T) {
+	type Login struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+	assert.Equal(t,
+		`export const LoginSchema = z.object({
+  email: z.string(),
+})
+export type Login = z.infer<typeof LoginSchema>
+
+`,
+		StructToZodSchema(Login{}))
+}
+
+func TestValidationTagsCustomKey(t *testing.T) {
+	type Login struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	c := NewConverter(nil, WithValidationTags("binding"))
+	assert.Equal(t,
+		`export const LoginSchema = z.object({
+  email: z.string().min(1).email(),
+})
+export type Login = z.infer<typeof LoginSchema>
+
+`,
+		c.Convert(Login{}))
+}
+
+func TestValidationTagsDiagnostics(t *testing.T) {
+	type Profile struct {
+		Bio      string            `json:"bio" validate:"max=200,excludesall=<>"`
+		Name     string            `json:"name" validate:"alpha|numeric"`
+		Count    int               `json:"count" validate:"dive,min=1"`
+		Meta     map[string]string `json:"meta" validate:"min=1"`
+		Comma    string            `json:"comma" validate:"contains=0x2C"`
+		Mood     Colour            `json:"mood" validate:"required,min=3"`
+		Priority int               `json:"priority" validate:"oneof=low high"`
+	}
+
+	c := NewConverter(nil, WithValidationTags("validate"))
+	require.NoError(t, c.RegisterEnum(ColourRed, ColourBlue))
+
+	assert.Equal(t,
+		`export const ColourSchema = z.enum(["red", "blue"])
+export type Colour = z.infer<typeof ColourSchema>
+
+export const ProfileSchema = z.object({
+  bio: z.string().max(200),
+  name: z.string(),
+  count: z.number().int(),
+  meta: z.record(z.string(), z.string()).nullable(),
+  comma: z.string().includes(","),
+  mood: ColourSchema,
+  priority: z.number().int(),
+})
+export type Profile = z.infer<typeof ProfileSchema>
+
+`,
+		c.Convert(Profile{}))
+
+	messages := []string{}
+	for _, d := range c.Diagnostics() {
+		assert.Equal(t, ErrUnknownValidation, d.Code)
+		messages = append(messages, d.Error())
+	}
+	assert.Equal(t, []string{
+		"Profile.Bio: rule excludesall=<> is not supported for string (unknown_validation)",
+		"Profile.Name: alternative rules are not supported: alpha|numeric (unknown_validation)",
+		"Profile.Count: dive is only applied to slices, arrays and maps, not int (unknown_validation)",
+		"Profile.Meta: rule min=1 is not supported for map (unknown_validation)",
+		"Profile.Mood: validation rules are not applied to custom types: required,min=3 (unknown_validation)",
+		"Profile.Priority: rule oneof=low high is not supported for int (unknown_validation)",
+	}, messages)
+}
+
+func TestValidationTagsInt64String(t *testing.T) {
+	type Ledger struct {
+		Total  int64  `json:"total" validate:"required"`
+		Limit  int64  `json:"limit" validate:"omitempty,min=1,max=10"`
+		Status uint64 `json:"status" validate:"oneof=1 2 3"`
+	}
+
+	c := NewConverter(nil, WithValidationTags("validate"), WithInt64Mode(Int64String))
+	assert.Equal(t,
+		`export const LedgerSchema = z.object({
+  total: z.string().regex(/^-?\d+$/).regex(/[1-9]/),
+  limit: z.string().regex(/^-?\d+$/).or(z.literal("0")),
+  status: z.enum(["1", "2", "3"]),
+})
+export type Ledger = z.infer<typeof LedgerSchema>
+
+`,
+		c.Convert(Ledger{}))
+
+	messages := []string{}
+	for _, d := range c.Diagnostics() {
+		messages = append(messages, d.Error())
+	}
+	assert.Equal(t, []string{
+		"Ledger.Limit: rule min=1 is not supported for int64 written as a string (unknown_validation)",
+		"Ledger.Limit: rule max=10 is not supported for int64 written as a string (unknown_validation)",
+	}, messages)
+}
+
+func TestValidationTagsInt64BigIntParams(t *testing.T) {
+	type Ledger struct {
+		Total int64 `json:"total" validate:"gte=1.5,lte=100"`
+	}
+
+	c := NewConverter(nil, WithValidationTags("validate"), WithInt64Mode(Int64BigInt))
+	assert.Equal(t,
+		`export const LedgerSchema = z.object({
+  total: z.bigint().min(-9223372036854775808n).max(9223372036854775807n).lte(100n),
+})
+export type Ledger = z.infer<typeof LedgerSchema>
+
+`,
+		c.Convert(Ledger{}))
+
+	require.Len(t, c.Diagnostics(), 1)
+	assert.Equal(t, "Ledger.Total: rule gte=1.5 is not supported for int64 (unknown_validation)", c.Diagnostics()[0].Error())
+}
+
+type Category struct {
+	Name     string      `json:"name" validate:"required"`
+	Parent   *Category   `json:"parent" validate:"required"`
+	Children []*Category `json:"children,omitempty" validate:"max=10"`
+}
+
+func TestValidationTagsRecursive(t *testing.T) {
+	c := NewConverter(nil, WithValidationTags("validate"))
+	assert.Equal(t,
+		`export type Category = {
+  name: string
+  parent: Category
+  children?: Category[]
+}
+export const CategorySchema: z.ZodType<Category> = z.object({
+  name: z.string().min(1),
+  parent: z.lazy(() => CategorySchema),
+  children: z.lazy(() => CategorySchema).array().max(10).optional(),
+})
+
+`,
+		c.Convert(Category{}))
+}
+
+func TestValidationTagsRecursiveDiagnostics(t *testing.T) {
+	type Thread struct {
+		Title   string    `json:"title" validate:"excludesall=<>"`
+		Replies []*Thread `json:"replies"`
+	}
+
+	// recursive structs are only walked once, so each problem is reported
+	// once.
+	c := NewConverter(nil, WithValidationTags("validate"))
+	c.Convert(Thread{})
+
+	require.Len(t, c.Diagnostics(), 1)
+	assert.Equal(t, "Thread.Title: rule excludesall=<> is not supported for string (unknown_validation)", c.Diagnostics()[0].Error())
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"maps"
 	"reflect"
-	"regexp"
 	"slices"
 	"sort"
 	"strings"
@@ -39,43 +38,13 @@ func NewConverter(custom map[string]CustomFn, opts ...Option) Converter {
 }
 
 func (c *Converter) Convert(input interface{}) string {
-	t := reflect.TypeOf(input)
+	c.convertTopLevel(reflect.TypeOf(input))
 
-	c.addSchema(t.Name(), c.convertStructTopLevel(t))
-
-	output := strings.Builder{}
-	sorted := []entry{}
-	for _, ent := range c.outputs {
-		sorted = append(sorted, ent)
-	}
-
-	sort.Sort(ByOrder(sorted))
-
-	for _, ent := range sorted {
-		output.WriteString(ent.data)
-		output.WriteString("\n\n")
-	}
-	return output.String()
+	return c.render(c.zodPrinter())
 }
 
 func (c *Converter) ConvertSlice(inputs []interface{}) string {
-	for _, input := range inputs {
-		t := reflect.TypeOf(input)
-		c.addSchema(t.Name(), c.convertStructTopLevel(t))
-	}
-	output := strings.Builder{}
-	sorted := []entry{}
-	for _, ent := range c.outputs {
-		sorted = append(sorted, ent)
-	}
-
-	sort.Sort(ByOrder(sorted))
-
-	for _, ent := range sorted {
-		output.WriteString(ent.data)
-		output.WriteString("\n\n")
-	}
-	return output.String()
+	return c.ConvertLibrary(c.zodPrinter(), inputs)
 }
 
 func StructToZodSchema(input interface{}, opts ...Option) string {
@@ -88,23 +57,9 @@ func StructToZodSchema(input interface{}, opts ...Option) string {
 		opt.apply(&c)
 	}
 
-	t := reflect.TypeOf(input)
-
-	c.addSchema(t.Name(), c.convertStructTopLevel(t))
-
-	output := strings.Builder{}
-	sorted := []entry{}
-	for _, ent := range c.outputs {
-		sorted = append(sorted, ent)
-	}
-
-	sort.Sort(ByOrder(sorted))
+	c.convertTopLevel(reflect.TypeOf(input))
 
-	for _, ent := range sorted {
-		output.WriteString(ent.data)
-		output.WriteString("\n\n")
-	}
-	return output.String()
+	return c.render(c.zodPrinter())
 }
 
 func StructToZodSchemaWithPrefix(prefix string, input interface{}, opts ...Option) string {
@@ -117,75 +72,232 @@ func StructToZodSchemaWithPrefix(prefix string, input interface{}, opts ...Optio
 		opt.apply(&c)
 	}
 
-	t := reflect.TypeOf(input)
+	c.convertTopLevel(reflect.TypeOf(input))
+
+	return c.render(c.zodPrinter())
+}
 
-	c.addSchema(t.Name(), c.convertStructTopLevel(t))
+func (c *Converter) convertTopLevel(t reflect.Type) {
+	c.path = []string{t.Name()}
+	defer func() { c.path = nil }()
 
-	output := strings.Builder{}
-	sorted := []entry{}
-	for _, ent := range c.outputs {
-		sorted = append(sorted, ent)
+	if t.Kind() != reflect.Struct {
+		c.fail(t, ErrUnsupportedType, fmt.Sprint("top level type must be a struct, got: ", t.Kind()))
+		return
 	}
 
-	sort.Sort(ByOrder(sorted))
+	if isGeneric(t) && c.genericNaming == nil {
+		// an instantiation is not declared itself, only the factory it uses.
+		c.convertSchema(t, t.Name(), 0)
+		return
+	}
+
+	c.addSchema(c.convertStructTopLevel(t))
+}
 
-	for _, ent := range sorted {
-		output.WriteString(ent.data)
+// writes out every schema so that each one is declared after the schemas it
+// depends on.
+func (c *Converter) render(l Library) string {
+	output := strings.Builder{}
+	if c.preamble != nil {
+		c.checkImport(l, *c.preamble)
+		output.WriteString(c.preamble.header(sources(c.outputs)))
+		output.WriteString(l.Import(*c.preamble))
+		output.WriteString("\n")
+	}
+	for _, d := range c.declarations(c.outputs) {
+		output.WriteString(l.Declaration(d))
 		output.WriteString("\n\n")
 	}
 	return output.String()
 }
 
-var typeMapping = map[reflect.Kind]string{
-	reflect.Bool:       "boolean",
-	reflect.Int:        "number",
-	reflect.Int8:       "number",
-	reflect.Int16:      "number",
-	reflect.Int32:      "number",
-	reflect.Int64:      "number",
-	reflect.Uint:       "number",
-	reflect.Uint8:      "number",
-	reflect.Uint16:     "number",
-	reflect.Uint32:     "number",
-	reflect.Uint64:     "number",
-	reflect.Uintptr:    "number",
-	reflect.Float32:    "number",
-	reflect.Float64:    "number",
-	reflect.Complex64:  "number",
-	reflect.Complex128: "number",
-	reflect.String:     "string",
-	reflect.Interface:  "any",
+var typeMapping = map[reflect.Kind]PrimitiveKind{
+	reflect.Bool:       PrimitiveBoolean,
+	reflect.Int:        PrimitiveNumber,
+	reflect.Int8:       PrimitiveNumber,
+	reflect.Int16:      PrimitiveNumber,
+	reflect.Int32:      PrimitiveNumber,
+	reflect.Int64:      PrimitiveNumber,
+	reflect.Uint:       PrimitiveNumber,
+	reflect.Uint8:      PrimitiveNumber,
+	reflect.Uint16:     PrimitiveNumber,
+	reflect.Uint32:     PrimitiveNumber,
+	reflect.Uint64:     PrimitiveNumber,
+	reflect.Uintptr:    PrimitiveNumber,
+	reflect.Float32:    PrimitiveNumber,
+	reflect.Float64:    PrimitiveNumber,
+	reflect.Complex64:  PrimitiveNumber,
+	reflect.Complex128: PrimitiveNumber,
+	reflect.String:     PrimitiveString,
+	reflect.Interface:  PrimitiveAny,
 }
 
 type entry struct {
-	order int
-	data  string
+	name string
+	decl *Declaration
+	// the keys of the schemas that must be declared before this one.
+	deps []string
 }
 
-type ByOrder []entry
-
-func (a ByOrder) Len() int           { return len(a) }
-func (a ByOrder) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
-func (a ByOrder) Less(i, j int) bool { return a[i].order < a[j].order }
-
 type CustomFn func(*Converter, reflect.Type, string, string, int) string
 
 type Converter struct {
-	prefix              string
-	structs             int
+	prefix string
+	// schemas keyed by the full package path and name of their type.
 	outputs             map[string]entry
 	custom              map[string]CustomFn
 	strictCustomSchemas bool
-}
 
-func (c *Converter) addSchema(name string, data string) {
+	// the keys of the structs currently being converted, outermost first.
+	// this is used to detect cycles in the type graph.
+	stack []string
+	// structs that take part in a cycle and must be declared with z.lazy
+	recursive map[string]bool
+	// the schemas referenced by each struct, these form the edges of the graph
+	// that decides the order in which schemas are written out.
+	deps map[string]map[string]bool
+
+	// the Go field path to the type currently being converted, for errors.
+	path []string
+	// when set, problems are collected in errs instead of panicking.
+	collect bool
+	errs    ConversionErrors
+
+	// the schema name given to each type key, and the reverse.
+	names  map[string]string
+	owners map[string]string
+	types  map[string]reflect.Type
+	// resolves name collisions, when nil collisions are errors.
+	collision func(t reflect.Type, name string) (string, bool)
+
+	// the type parameters of the generic struct currently being converted, and
+	// the field paths where they are used, relative to the path at paramsAt.
+	params    []genericParam
+	paramUses map[string][]string
+	paramsAt  int
+	// where each generic struct uses its type parameters, as found in the
+	// instantiation its factory was built from, keyed like outputs. every
+	// other instantiation is checked against it once, and whether it fits is
+	// kept.
+	genericUses map[string]map[string][]string
+	genericFits map[reflect.Type]bool
+	// when set, generic instantiations are emitted as concrete schemas.
+	genericNaming *GenericNaming
+	// how fields with the `json:",string"` option are converted.
+	quotedMode QuotedMode
+	// how 64 bit integers are converted.
+	int64Mode Int64Mode
+	// how fixed size arrays are converted.
+	arrayMode ArrayMode
+	// registered enums, keyed by the type they belong to.
+	enums map[string]enum
+	// where the values of enums that have not been registered are found, and
+	// the types it has already been asked about.
+	enumSource   EnumSource
+	enumsChecked map[string]bool
+	// the struct tag that validation rules are read from, if any.
+	validationTag string
+	// problems that did not stop the conversion.
+	diagnostics ConversionErrors
+	// registered unions, keyed by their interface type, and the discriminator
+	// field of each of their members.
+	unions         map[string]union
+	discriminators map[reflect.Type]discriminatorField
+	// where the doc comments of types and fields are found, if anywhere.
+	docSource DocSource
+	// the schemas and types that each schema refers to, including lazy
+	// references, which become imports when schemas are split into modules.
+	refs map[string]map[string]reference
+	// how schemas are split into modules by ConvertModules.
+	moduleLayout ModuleLayout
+	// what is written before the schemas, if anything.
+	preamble *Preamble
+	// the JSON Schemas of custom types that have them, keyed like custom.
+	customJSONSchemas map[string]string
+	// the schemas of custom types for other libraries, keyed by the name of
+	// the library and then like custom.
+	librarySchemas map[string]map[string]string
+}
+
+func (c *Converter) addSchema(d *Declaration) {
 	//First check if the object already exists. If it does do not replace. This is needed for second order
-	_, ok := c.outputs[name]
+	_, ok := c.outputs[d.Key]
 	if !ok {
-		order := c.structs
-		c.outputs[name] = entry{order, data}
-		c.structs = order + 1
+		deps := slices.Collect(maps.Keys(c.deps[d.Key]))
+		sort.Strings(deps)
+		c.outputs[d.Key] = entry{d.Name, d, deps}
+	}
+}
+
+// builds the declaration of a named type. the TypeScript type of a declaration
+// that refers to itself is written out by hand, so it uses the types of the
+// declarations it refers to as well as their schemas.
+func (c *Converter) declare(t reflect.Type, key, name string, schema Schema, recursive bool) *Declaration {
+	doc := c.typeDoc(t)
+	if recursive {
+		walkSchema(schema, func(s Schema) {
+			if ref, ok := s.(*Reference); ok {
+				c.addReferenceFrom(key, ref.Key, referenceType)
+			}
+		})
+	}
+	return &Declaration{
+		Key:         key,
+		Name:        name,
+		Type:        t,
+		Schema:      schema,
+		Recursive:   recursive,
+		Doc:         doc,
+		Description: docDescription(doc),
+	}
+}
+
+// records that the struct at the top of the stack refers to another schema.
+// lazy references are not recorded as they are resolved after declaration.
+func (c *Converter) addDependency(key string) {
+	c.addReference(key, referenceSchema)
+	if len(c.stack) == 0 {
+		return
+	}
+	top := c.stack[len(c.stack)-1]
+	if top == key {
+		return
+	}
+	if c.deps == nil {
+		c.deps = make(map[string]map[string]bool)
+	}
+	if c.deps[top] == nil {
+		c.deps[top] = make(map[string]bool)
 	}
+	c.deps[top][key] = true
+}
+
+// pushes a struct onto the conversion stack, this must be paired with a pop.
+func (c *Converter) push(key string) {
+	c.stack = append(c.stack, key)
+}
+
+func (c *Converter) pop() {
+	c.stack = c.stack[:len(c.stack)-1]
+}
+
+// checks whether a struct is currently being converted further up the stack,
+// if it is, every struct between that point and the top of the stack is part of
+// a cycle and must be marked as recursive.
+func (c *Converter) isCycle(key string) bool {
+	for i, k := range c.stack {
+		if k == key {
+			if c.recursive == nil {
+				c.recursive = make(map[string]bool)
+			}
+			for _, r := range c.stack[i:] {
+				c.recursive[r] = true
+			}
+			return true
+		}
+	}
+	return false
 }
 
 func schemaName(prefix, name string) string {
@@ -193,16 +305,13 @@ func schemaName(prefix, name string) string {
 }
 
 func fieldName(input reflect.StructField) string {
-	if json := input.Tag.Get("json"); json != "" {
-		args := strings.Split(json, ",")
-		if len(args[0]) > 0 {
-			return args[0]
-		}
-		// This is also valid:
-		// json:",omitempty"
-		// so in this case, args[0] will be empty, so fall through to using the
-		// raw field name.
+	if name, _ := parseTag(input.Tag.Get("json")); isValidTag(name) {
+		return name
 	}
+	// This is also valid:
+	// json:",omitempty"
+	// so in this case, the name will be empty, so fall through to using the
+	// raw field name.
 
 	// When Golang marshals a struct to JSON and it doesn't have any JSON tags
 	// that give the fields names, it defaults to just using the field's name.
@@ -216,7 +325,7 @@ func typeName(t reflect.Type) string {
 	if t.Kind() == reflect.Ptr {
 		return typeName(t.Elem())
 	}
-	if t.Kind() == reflect.Slice {
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
 		return typeName(t.Elem())
 	}
 	if t.Kind() == reflect.Map {
@@ -226,99 +335,108 @@ func typeName(t reflect.Type) string {
 	return "UNKNOWN"
 }
 
-func (c *Converter) convertStructTopLevel(t reflect.Type) string {
-	output := strings.Builder{}
+// refers to the schema of a named struct, declaring it if it does not exist
+// yet.
+func (c *Converter) convertNamedStruct(t reflect.Type) Schema {
+	key := typeKey(t)
+	if c.isCycle(key) {
+		// the schema is still being declared so it must be referenced lazily.
+		c.addReference(key, referenceSchema)
+		return &Reference{Key: key, Name: c.nameFor(t), Lazy: true}
+	}
 
-	name := t.Name()
+	if _, ok := c.outputs[key]; !ok {
+		c.addSchema(c.convertStructTopLevel(t))
+	}
+	c.addDependency(key)
+	return &Reference{Key: key, Name: c.nameFor(t)}
+}
 
-	output.WriteString(fmt.Sprintf(
-		`export const %s = %s
-`,
-		schemaName(c.prefix, name), c.convertStruct(t, 0)))
+func (c *Converter) convertStructTopLevel(t reflect.Type) *Declaration {
+	key := typeKey(t)
+	name := c.nameFor(t)
 
-	output.WriteString(fmt.Sprintf(`export type %s%s = z.infer<typeof %s%sSchema>`,
-		c.prefix, name, c.prefix, name))
+	// type parameters only apply to the generic struct that declares them, not
+	// to other structs it refers to.
+	params := c.params
+	c.params = nil
+	c.push(key)
+	schema := c.convertStruct(t, 0)
+	c.pop()
+	c.params = params
 
-	return output.String()
+	return c.declare(t, key, name, schema, c.recursive[key])
 }
 
-func (c *Converter) convertStruct(input reflect.Type, indent int) string {
-	output := strings.Builder{}
+func (c *Converter) convertStruct(input reflect.Type, indent int) *Object {
+	return &Object{Fields: c.convertStructFields(input, indent+1)}
+}
 
-	output.WriteString(`z.object({
-`)
+// how a single field is converted, as decided by convertStructFields.
+type fieldOptions struct {
+	optional bool
+	nullable bool
+	// the JSON literal the field is fixed to, such as the discriminator of a
+	// union member.
+	literal string
+	// the doc comment of the field.
+	doc string
+	// the parsed `zod` tag of the field.
+	tag zodTag
+}
 
-	c.convertStructFields(&output, input, indent+1, []map[string]string{}, make(map[string]any))
+func (c *Converter) convertStructFields(structType reflect.Type, indent int) []*Field {
+	fields := []*Field{}
 
-	output.WriteString(indentation(indent))
-	output.WriteString(`})`)
+	// a field tagged with a name prefixed with `-` skips any field with that
+	// name that comes after it, usually one from an embedded struct.
+	toSkip := map[string]bool{}
 
-	return output.String()
-}
+	for _, field := range structFields(structType) {
+		if len(field.name) > 1 && field.name[0] == '-' {
+			toSkip[field.name[1:]] = true
+			continue
+		}
+		if toSkip[field.name] {
+			continue
+		}
 
-func fieldExists(fields []map[string]string, name string) bool {
-	for _, m := range fields {
-		if m[name] != "" {
-			return true
+		for _, name := range field.path {
+			c.pushPath("." + name)
 		}
-	}
-	return false
-}
 
-func (c *Converter) convertStructFields(
-	output *strings.Builder,
-	structType reflect.Type,
-	indent int,
-	fields []map[string]string,
-	toSkip map[string]any,
-) {
-	// the original algorithm employs stateless depth-first recursion.
-	// because we now need to keep track of state, the entire struct must
-	// be traversed before the correct zod output can be generated. fields
-	// must be accumulated as a slice (because maps are unordered)
-
-	for i := 0; i < structType.NumField(); i++ {
-		field := structType.Field(i)
-		shouldInlineField := structType.Name() == "" && field.Anonymous ||
-			field.Tag.Get("json") == ",inline"
-		if shouldInlineField {
-			inlineStruct := field.Type
-			if inlineStruct.Kind() == reflect.Ptr {
-				inlineStruct = inlineStruct.Elem()
+		tag := c.zodTag(field.field)
+		if !tag.exclude {
+			optional, nullable := isOptional(field.field), isNullable(field.field)
+			if c.isRequired(field.field) {
+				// validation rejects missing and null values, so they are never sent.
+				optional, nullable = false, false
 			}
-			c.convertStructFields(output, inlineStruct, indent, fields, toSkip)
-		} else {
-			name := fieldName(field)
-			if name == "-" || fieldExists(fields, name) {
-				continue
+			if c.isUnion(field.field.Type) {
+				// unlike other interfaces, unions do not include null.
+				optional = strings.Contains(field.field.Tag.Get("json"), "omitempty")
+				nullable = !optional
 			}
-
-			if name[0] == '-' {
-				toSkip[name[1:]] = 1
-				continue
+			if field.viaPointer {
+				// encoding/json leaves out every field of a nil embedded pointer.
+				optional = true
 			}
-
-			optional := isOptional(field)
-			nullable := isNullable(field)
-			line := c.convertField(field, indent, optional, nullable)
-			fields = append(fields, map[string]string{name: line})
+			fields = append(fields, c.convertField(field.name, field.field, indent, fieldOptions{
+				optional: optional || tag.optional || tag.nullish,
+				nullable: nullable || tag.nullable || tag.nullish,
+				literal:  c.discriminatorFor(structType, field.name),
+				doc:      c.fieldDoc(fieldOwner(structType, field.index), field.field),
+				tag:      tag,
+			}))
 		}
-	}
-
-	for _, field := range fields {
-		k := slices.Collect(maps.Keys(field))[0]
-		v := field[k]
 
-		if _, ok := toSkip[k]; ok {
-			continue
+		for range field.path {
+			c.popPath()
 		}
-		toSkip[k] = 1
-
-		output.WriteString(v)
 	}
-}
 
-var matchGenericTypeName = regexp.MustCompile(`(.+)\[(.+)\]`)
+	return fields
+}
 
 // checking it a reflected type is a generic isn't supported as far as I can see
 // so this simple check looks for a `[` character in the type name: `T1[T2]`.
@@ -332,10 +450,12 @@ func getFullName(t reflect.Type) (string, string) {
 	var generic string
 
 	if isGeneric(t) {
-		m := matchGenericTypeName.FindAllStringSubmatch(t.Name(), 1)[0]
+		// the type arguments may themselves be generic, so split on the first
+		// bracket rather than matching the innermost pair.
+		i := strings.Index(t.Name(), "[")
 
-		typename = m[1]
-		generic = m[2]
+		typename = t.Name()[:i]
+		generic = t.Name()[i+1 : len(t.Name())-1]
 	} else {
 		typename = t.Name()
 	}
@@ -368,128 +488,191 @@ func (c *Converter) isCustom(t reflect.Type) bool {
 		ptrT.Implements(reflect.TypeOf((*DynamicFunctionSchema)(nil)).Elem())
 }
 
-func (c *Converter) handleCustomType(t reflect.Type, name string, indent int) (string, bool) {
+func (c *Converter) handleCustomType(t reflect.Type, name string, indent int) (Schema, bool) {
 	fullName, generic := getFullName(t)
 
 	custom, ok := c.custom[fullName]
 	if ok {
-		return custom(c, t, name, generic, indent), true
+		return &Custom{Type: t, Zod: custom(c, t, name, generic, indent)}, true
 	}
 
-	switch v := reflect.Zero(t).Interface().(type) {
-	case ConstantSchema:
-		return v.ZodSchema(), true
-	case DynamicSchema:
-		return v.ZodSchema(c, t, name, generic, indent), true
-	case DynamicFunctionSchema:
-		return v.ZodSchema(c.ConvertType, t, name, generic, indent), true
-	}
-	switch v := reflect.Zero(reflect.PointerTo(t)).Interface().(type) {
-	case ConstantSchema:
-		return v.ZodSchema(), true
-	case DynamicSchema:
-		return v.ZodSchema(c, t, name, generic, indent), true
-	case DynamicFunctionSchema:
-		return v.ZodSchema(c.ConvertType, t, name, generic, indent), true
+	for _, v := range []interface{}{reflect.Zero(t).Interface(), reflect.Zero(reflect.PointerTo(t)).Interface()} {
+		switch v := v.(type) {
+		case ConstantSchema:
+			return &Custom{Type: t, Zod: v.ZodSchema()}, true
+		case DynamicSchema:
+			return &Custom{Type: t, Zod: v.ZodSchema(c, t, name, generic, indent)}, true
+		case DynamicFunctionSchema:
+			return &Custom{Type: t, Zod: v.ZodSchema(c.ConvertType, t, name, generic, indent)}, true
+		}
 	}
 
 	if _, ok := t.MethodByName("ZodSchema"); ok {
-		panic(fmt.Sprint("found a ZodSchema method with unexpected signature on type: ", fullName))
+		c.fail(t, ErrInvalidSchemaMethod, fmt.Sprint("found a ZodSchema method with unexpected signature on type: ", fullName))
+		return &Primitive{Kind: PrimitiveUnknown}, true
+	}
+
+	return nil, false
+}
+
+// fills in the schemas that a custom type supplies for backends other than Zod.
+func (c *Converter) addCustomSchemas(custom *Custom, t reflect.Type) {
+	custom.JSONSchema = c.customJSONSchema(t)
+	custom.Libraries = c.customLibrarySchemas(t)
+}
+
+// gets the schema that a custom type supplies for a backend, either from the
+// schemas given by its full name or from the method of the backend.
+func customSchema[S any](t reflect.Type, schemas map[string]string, method func(S) string) string {
+	fullName, _ := getFullName(t)
+	if schema, ok := schemas[fullName]; ok {
+		return schema
 	}
 
-	return "", false
+	for _, v := range []interface{}{reflect.Zero(t).Interface(), reflect.Zero(reflect.PointerTo(t)).Interface()} {
+		if v, ok := v.(S); ok {
+			return method(v)
+		}
+	}
+	return ""
 }
 
+// ConvertType converts a type to a Zod schema, declaring the schemas of any
+// named types it uses. It is given to DynamicFunctionSchema so that custom
+// schemas can be built from the schemas of other types.
 func (c *Converter) ConvertType(t reflect.Type, name string, indent int) string {
+	return c.zodPrinter().Schema(c.convertSchema(t, name, indent), indent)
+}
+
+func (c *Converter) convertSchema(t reflect.Type, name string, indent int) Schema {
+	if param, ok := c.genericParam(t); ok {
+		return param
+	}
+
 	if t.Kind() == reflect.Ptr {
 		inner := t.Elem()
-		return c.ConvertType(inner, name, indent)
+		return c.convertSchema(inner, name, indent)
+	}
+
+	if c.isEnum(t) {
+		return c.convertEnum(t)
+	}
+
+	if u, ok := c.unionFor(t); ok {
+		return c.convertUnion(t, u)
 	}
 
 	if custom, ok := c.handleCustomType(t, name, indent); ok {
+		if custom, ok := custom.(*Custom); ok {
+			c.addCustomSchemas(custom, t)
+		}
 		return custom
 	}
 
 	fullName, _ := getFullName(t)
 	if fullName == "time.Time" {
 		// timestamps are serialised to strings.
-		return "z.string()"
+		return &Primitive{Kind: PrimitiveString, Format: "date-time"}
 	}
 
 	if c.strictCustomSchemas &&
 		(t.Implements(reflect.TypeOf((*json.Marshaler)(nil)).Elem()) ||
 			reflect.PointerTo(t).Implements(reflect.TypeOf((*json.Marshaler)(nil)).Elem())) {
-		panic(fmt.Sprint("found type with custom marshalling but no custom schema: ", fullName))
+		c.fail(t, ErrMissingCustomSchema, fmt.Sprint("found type with custom marshalling but no custom schema: ", fullName))
 	}
 
 	if t.Kind() == reflect.Slice {
 		if t.Elem().Kind() == reflect.Uint8 {
 			// Per https://pkg.go.dev/encoding/json#Marshal, []byte is marshalled as a
 			// base64-encoded string.
-			return "z.string()"
+			return &Primitive{Kind: PrimitiveString, Format: "base64"}
 		}
 
-		return fmt.Sprintf(
-			"%s.array()",
-			c.ConvertType(t.Elem(), name, indent))
+		c.pushPath("[]")
+		defer c.popPath()
+
+		return &Array{Items: c.convertSchema(t.Elem(), name, indent)}
+	}
+
+	if t.Kind() == reflect.Array {
+		return c.convertArray(t, name, indent)
 	}
 
 	if t.Kind() == reflect.Struct {
 		// Handle nested un-named structs - these are inline.
 		if t.Name() == "" {
 			return c.convertStruct(t, indent)
-		} else {
-			c.addSchema(name, c.convertStructTopLevel(t))
-			return schemaName(c.prefix, name)
+		} else if isGeneric(t) && c.genericNaming == nil {
+			return c.convertGeneric(t, indent)
 		}
+		return c.convertNamedStruct(t)
 	}
 
 	if t.Kind() == reflect.Map {
 		return c.convertMap(t, name, indent)
 	}
 
-	ztype, ok := typeMapping[t.Kind()]
+	if isInteger(t) {
+		return c.convertInteger(t, false)
+	}
+
+	kind, ok := typeMapping[t.Kind()]
 	if !ok {
-		panic(fmt.Sprint("cannot handle: ", t.Kind()))
+		c.fail(t, ErrUnsupportedType, fmt.Sprint("cannot handle: ", t.Kind()))
+		return &Primitive{Kind: PrimitiveUnknown}
 	}
 
-	return fmt.Sprintf("z.%s()", ztype)
+	return &Primitive{Kind: kind}
 }
 
-func (c *Converter) convertField(f reflect.StructField, indent int, optional, nullable bool) string {
-	name := fieldName(f)
-
-	// fields named `-` are not exported to JSON so don't export zod types
-	if name == "-" {
-		return ""
-	}
-
+func (c *Converter) convertField(name string, f reflect.StructField, indent int, opts fieldOptions) *Field {
 	// because nullability is processed before custom types, this makes sure
-	// the custom type has control over nullability.
-	isCustom := c.isCustom(f.Type)
-
-	optionalCall := ""
-	if optional {
-		optionalCall = ".optional()"
-	}
-	nullableCall := ""
-	if nullable && !isCustom {
-		nullableCall = ".nullable()"
+	// the custom type has control over nullability. registered enums are
+	// converted by the converter itself, so they are not treated as custom.
+	isCustom := c.isCustom(f.Type) && !c.isEnum(f.Type)
+	tag := opts.tag
+
+	field := &Field{
+		Name:        name,
+		Optional:    opts.optional,
+		Nullable:    opts.nullable && (!isCustom || tag.nullable || tag.nullish),
+		Nullish:     tag.nullish,
+		Methods:     tag.methods,
+		Default:     tag.def,
+		Doc:         opts.doc,
+		Description: docDescription(opts.doc),
+	}
+	if tag.describe != "" {
+		field.Description = tag.describe
+	}
+
+	if opts.literal != "" {
+		field.Schema = &Const{Value: opts.literal}
+	} else if tag.schema != "" {
+		field.Schema = &Custom{Zod: tag.schema}
+	} else if quoted, ok := c.quotedType(f); ok {
+		field.Schema = c.convertQuoted(quoted)
+		if rules := c.validationRules(f); len(rules) > 0 {
+			c.warn(f.Type, ErrUnknownValidation, fmt.Sprintf("validation rules are not applied to string encoded fields: %s", strings.Join(rules, ",")))
+		}
+	} else if rules := c.validationRules(f); len(rules) > 0 {
+		field.Schema = c.convertValidated(f.Type, rules, typeName(f.Type), indent)
+	} else {
+		field.Schema = c.convertSchema(f.Type, typeName(f.Type), indent)
 	}
 
-	return fmt.Sprintf(
-		"%s%s: %s%s%s,\n",
-		indentation(indent),
-		name,
-		c.ConvertType(f.Type, typeName(f.Type), indent),
-		optionalCall,
-		nullableCall)
+	return field
 }
 
-func (c *Converter) convertMap(t reflect.Type, name string, indent int) string {
-	return fmt.Sprintf(`z.record(%s, %s)`,
-		c.ConvertType(t.Key(), name, indent),
-		c.ConvertType(t.Elem(), name, indent))
+func (c *Converter) convertMap(t reflect.Type, name string, indent int) Schema {
+	c.pushPath("[key]")
+	key := c.convertSchema(t.Key(), name, indent)
+	c.popPath()
+	c.pushPath("[value]")
+	value := c.convertSchema(t.Elem(), name, indent)
+	c.popPath()
+
+	return &Record{Key: key, Value: value}
 }
 
 func isNullable(field reflect.StructField) bool {
@@ -534,6 +717,10 @@ func isOptional(field reflect.StructField) bool {
 	if field.Type.Kind() == reflect.Struct || isInterface(field) {
 		return false
 	}
+	// Arrays are only empty when their length is zero, so the rest are never omitted.
+	if field.Type.Kind() == reflect.Array && field.Type.Len() > 0 {
+		return false
+	}
 	// Otherwise, omitempty zero-values are omitted and are mapped to undefined in JS/TS.
 	return strings.Contains(field.Tag.Get("json"), "omitempty")
 }
@@ -69,7 +69,7 @@ func TestStructSimple(t *testing.T) {
 	assert.Equal(t,
 		`export const UserSchema = z.object({
   Name: z.string(),
-  Age: z.number(),
+  Age: z.number().int(),
   Height: z.number(),
 })
 export type User = z.infer<typeof UserSchema>
@@ -88,7 +88,7 @@ func TestStructSimpleWithOmittedField(t *testing.T) {
 	assert.Equal(t,
 		`export const UserSchema = z.object({
   Name: z.string(),
-  Age: z.number(),
+  Age: z.number().int(),
   Height: z.number(),
 })
 export type User = z.infer<typeof UserSchema>
@@ -106,7 +106,7 @@ func TestStructSimplePrefix(t *testing.T) {
 	assert.Equal(t,
 		`export const BotUserSchema = z.object({
   Name: z.string(),
-  Age: z.number(),
+  Age: z.number().int(),
   Height: z.number(),
 })
 export type BotUser = z.infer<typeof BotUserSchema>
@@ -448,7 +448,7 @@ export type PostWithMetaData = z.infer<typeof PostWithMetaDataSchema>
 export const UserSchema = z.object({
   Name: z.string(),
   Nickname: z.string().nullable(),
-  Age: z.number(),
+  Age: z.number().int(),
   Height: z.number(),
   OldPostWithMetaData: PostWithMetaDataSchema,
   Tags: z.string().array().nullable(),
@@ -498,12 +498,7 @@ func TestConvertSlice(t *testing.T) {
 		Whim{},
 	}
 	assert.Equal(t,
-		`export const ZipSchema = z.object({
-  Zap: FooSchema.nullable(),
-})
-export type Zip = z.infer<typeof ZipSchema>
-
-export const FooSchema = z.object({
+		`export const FooSchema = z.object({
   Bar: z.string(),
   Baz: z.string(),
   Quz: z.string(),
@@ -515,9 +510,51 @@ export const WhimSchema = z.object({
 })
 export type Whim = z.infer<typeof WhimSchema>
 
+export const ZipSchema = z.object({
+  Zap: FooSchema.nullable(),
+})
+export type Zip = z.infer<typeof ZipSchema>
+
 `, c.ConvertSlice(types))
 }
 
+func TestConvertSliceOrderIndependent(t *testing.T) {
+	type Author struct {
+		Name string
+	}
+	type Post struct {
+		Author Author
+	}
+	type User struct {
+		Posts []Post
+	}
+
+	c1 := NewConverter(map[string]CustomFn{})
+	c2 := NewConverter(map[string]CustomFn{})
+
+	assert.Equal(t,
+		`export const AuthorSchema = z.object({
+  Name: z.string(),
+})
+export type Author = z.infer<typeof AuthorSchema>
+
+export const PostSchema = z.object({
+  Author: AuthorSchema,
+})
+export type Post = z.infer<typeof PostSchema>
+
+export const UserSchema = z.object({
+  Posts: PostSchema.array().nullable(),
+})
+export type User = z.infer<typeof UserSchema>
+
+`, c1.ConvertSlice([]interface{}{User{}, Post{}, Author{}}))
+
+	assert.Equal(t,
+		c1.ConvertSlice([]interface{}{User{}, Post{}, Author{}}),
+		c2.ConvertSlice([]interface{}{Author{}, User{}, Post{}}))
+}
+
 func TestStructTime(t *testing.T) {
 	type User struct {
 		Name string
@@ -615,7 +652,7 @@ func TestZodSchemaDynamic(t *testing.T) {
 	assert.Equal(t,
 		`export const UserSchema = z.object({
   Nicknames: z.string().array(),
-  FavoriteNumbers: z.number().array(),
+  FavoriteNumbers: z.number().int().array(),
 })
 export type User = z.infer<typeof UserSchema>
 
@@ -637,7 +674,7 @@ func TestZodSchemaDynamicFunction(t *testing.T) {
 	assert.Equal(t,
 		`export const UserSchema = z.object({
   Nicknames: z.string().array(),
-  FavoriteNumbers: z.number().array(),
+  FavoriteNumbers: z.number().int().array(),
 })
 export type User = z.infer<typeof UserSchema>
 
@@ -683,7 +720,7 @@ func TestStrictCustom(t *testing.T) {
 	c2 := NewConverter(map[string]CustomFn{}, WithStrictCustomSchemas(false))
 	assert.Equal(t,
 		`export const JobSchema = z.object({
-  State: z.number(),
+  State: z.number().int(),
 })
 export type Job = z.infer<typeof JobSchema>
 
@@ -692,7 +729,7 @@ export type Job = z.infer<typeof JobSchema>
 	c3 := NewConverter(map[string]CustomFn{} /* defaults to false */)
 	assert.Equal(t,
 		`export const JobSchema = z.object({
-  State: z.number(),
+  State: z.number().int(),
 })
 export type Job = z.infer<typeof JobSchema>
 
@@ -722,7 +759,7 @@ func TestStrictCustomPtrReceiver(t *testing.T) {
 	c2 := NewConverter(map[string]CustomFn{}, WithStrictCustomSchemas(false))
 	assert.Equal(t,
 		`export const JobSchema = z.object({
-  State: z.number(),
+  State: z.number().int(),
 })
 export type Job = z.infer<typeof JobSchema>
 
@@ -731,7 +768,7 @@ export type Job = z.infer<typeof JobSchema>
 	c3 := NewConverter(map[string]CustomFn{} /* defaults to false */)
 	assert.Equal(t,
 		`export const JobSchema = z.object({
-  State: z.number(),
+  State: z.number().int(),
 })
 export type Job = z.infer<typeof JobSchema>
 
@@ -766,7 +803,7 @@ func TestInlineStructField(t *testing.T) {
 export type Embedded = z.infer<typeof EmbeddedSchema>
 
 export const TestSchema = z.object({
-  inlineField1: z.string(),
+  inlineField1: z.string().optional(),
   inlineField2: z.string().optional(),
   inlineField3: z.string(),
   embedded: EmbeddedSchema,
@@ -801,16 +838,16 @@ func TestDeeplyNestedInlineStructs(t *testing.T) {
 
 	assert.Equal(t,
 		`export const NestedStructSchema = z.object({
-  mainField: z.string(),
+  mainField: z.string().optional(),
   optionalInfo: z.string().optional(),
-  extraField1: z.number().optional(),
+  extraField1: z.number().int().optional(),
   extraField2: z.string().optional(),
   uniqueField: z.string(),
 })
 export type NestedStruct = z.infer<typeof NestedStructSchema>
 
 export const RootStructSchema = z.object({
-  mainField: z.string(),
+  mainField: z.string().optional(),
   optionalInfo: z.string().optional(),
   nestedArray: NestedStructSchema.array().nullable(),
 })
@@ -834,20 +871,12 @@ func TestMultipleLevelsInlineEmbedding(t *testing.T) {
 		TopField bool `json:"topField"`
 	}
 
+	// embedded structs are promoted into their parent, as encoding/json does.
+	// the fields of an embedded pointer are left out when it is nil.
 	assert.Equal(t,
-		`export const BaseStructSchema = z.object({
-  baseField: z.string(),
-})
-export type BaseStruct = z.infer<typeof BaseStructSchema>
-
-export const MiddleStructSchema = z.object({
-  BaseStruct: BaseStructSchema.nullable(),
-  middleField: z.number(),
-})
-export type MiddleStruct = z.infer<typeof MiddleStructSchema>
-
-export const TopStructSchema = z.object({
-  MiddleStruct: MiddleStructSchema,
+		`export const TopStructSchema = z.object({
+  baseField: z.string().optional(),
+  middleField: z.number().int(),
   topField: z.boolean(),
 })
 export type TopStruct = z.infer<typeof TopStructSchema>
@@ -911,8 +940,8 @@ export type FieldsSkipped = z.infer<typeof FieldsSkippedSchema>
 		`export const FieldsNotSkippedSchema = z.object({
   id: z.string(),
   name: z.string(),
-  start: z.number(),
-  end: z.number(),
+  start: z.number().int(),
+  end: z.number().int(),
 })
 export type FieldsNotSkipped = z.infer<typeof FieldsNotSkippedSchema>
 
@@ -947,3 +976,100 @@ export type BaseStruct = z.infer<typeof BaseStructSchema>
 
 `, StructToZodSchema(BaseStruct{}))
 }
+
+type Comment struct {
+	Body    string
+	Replies []Comment
+}
+
+func TestRecursiveStruct(t *testing.T) {
+	assert.Equal(t,
+		`export type Comment = {
+  Body: string
+  Replies: Comment[] | null
+}
+export const CommentSchema: z.ZodType<Comment> = z.object({
+  Body: z.string(),
+  Replies: z.lazy(() => CommentSchema).array().nullable(),
+})
+
+`, StructToZodSchema(Comment{}))
+}
+
+type Folder struct {
+	Name     string
+	Parent   *Folder `json:",omitempty"`
+	Children map[string]Folder
+	Metadata struct {
+		Owner *Folder
+	}
+}
+
+func TestRecursiveStructPointer(t *testing.T) {
+	assert.Equal(t,
+		`export type Folder = {
+  Name: string
+  Parent?: Folder
+  Children: Record<string, Folder> | null
+  Metadata: {
+    Owner: Folder | null
+  }
+}
+export const FolderSchema: z.ZodType<Folder> = z.object({
+  Name: z.string(),
+  Parent: z.lazy(() => FolderSchema).optional(),
+  Children: z.record(z.string(), z.lazy(() => FolderSchema)).nullable(),
+  Metadata: z.object({
+    Owner: z.lazy(() => FolderSchema).nullable(),
+  }),
+})
+
+`, StructToZodSchema(Folder{}))
+}
+
+type Employee struct {
+	Name       string
+	Department *Department
+}
+
+type Department struct {
+	Title   string
+	Manager Employee
+	Staff   []Employee
+}
+
+type Organisation struct {
+	Departments []Department
+	CEO         Employee
+}
+
+func TestMutuallyRecursiveStructs(t *testing.T) {
+	assert.Equal(t,
+		`export type Employee = {
+  Name: string
+  Department: Department | null
+}
+export const EmployeeSchema: z.ZodType<Employee> = z.object({
+  Name: z.string(),
+  Department: z.lazy(() => DepartmentSchema).nullable(),
+})
+
+export type Department = {
+  Title: string
+  Manager: Employee
+  Staff: Employee[] | null
+}
+export const DepartmentSchema: z.ZodType<Department> = z.object({
+  Title: z.string(),
+  Manager: EmployeeSchema,
+  Staff: EmployeeSchema.array().nullable(),
+})
+
+export const OrganisationSchema = z.object({
+  Departments: DepartmentSchema.array().nullable(),
+  CEO: EmployeeSchema,
+})
+export type Organisation = z.infer<typeof OrganisationSchema>
+
+`, StructToZodSchema(Organisation{}))
+}
@@ -0,0 +1,324 @@
+package supervillain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ZodMiniPrinter prints declarations as schemas for zod/mini, which has
+// functions such as `z.optional(s)` and checks such as
+// `.check(z.minLength(1))` in place of the methods of Zod. Convert and friends
+// use it when zod is imported from zod/mini by WithPreamble.
+//
+// Custom schemas, and the schemas and methods from the `zod` tag, are written
+// as they are given, so they must be written for zod/mini too.
+type ZodMiniPrinter struct {
+	// Prefix is added to the start of every declared name.
+	Prefix string
+}
+
+// Name is "zod/mini". Custom types give schemas for it under this name, or
+// otherwise their Zod schema is used.
+func (p ZodMiniPrinter) Name() string {
+	return "zod/mini"
+}
+
+// Import imports zod as the preamble says to.
+func (p ZodMiniPrinter) Import(preamble Preamble) string {
+	return preamble.importZod()
+}
+
+// Declaration prints the schema and type of a declaration, along with the
+// object of values of an enum.
+func (p ZodMiniPrinter) Declaration(d *Declaration) string {
+	name := p.Prefix + d.Name
+	schemaName := schemaName(p.Prefix, d.Name)
+	jsdoc, _ := formatDoc(d.Doc, 0)
+
+	schema := d.Schema
+	if u, ok := schema.(*Union); ok && d.Recursive {
+		// members that refer to themselves are annotated with z.ZodMiniType,
+		// which z.discriminatedUnion does not accept, so they fall back to
+		// z.union.
+		plain := *u
+		plain.Discriminator = ""
+		schema = &plain
+	}
+	zod := zodMiniDescribe(p.Schema(schema, 0), d.Description)
+
+	output := strings.Builder{}
+	switch {
+	case len(d.Params) > 0:
+		typeParams := make([]string, len(d.Params))
+		valueParams := make([]string, len(d.Params))
+		for i, param := range d.Params {
+			typeParams[i] = fmt.Sprintf("%s extends z.ZodMiniType", param)
+			valueParams[i] = fmt.Sprintf("%s: %s", strings.ToLower(param), param)
+		}
+		output.WriteString(fmt.Sprintf("export const %s = <%s>(%s) => %s\n",
+			schemaName, strings.Join(typeParams, ", "), strings.Join(valueParams, ", "), zod))
+		output.WriteString(jsdoc)
+		output.WriteString(fmt.Sprintf("export type %s<%s> = z.infer<ReturnType<typeof %s<%s>>>",
+			name, strings.Join(typeParams, ", "), schemaName, strings.Join(d.Params, ", ")))
+
+	case d.Recursive:
+		// z.infer cannot be used for types that refer to themselves so the type
+		// is written out by hand and the schema is annotated with it.
+		output.WriteString(jsdoc)
+		output.WriteString(fmt.Sprintf("export type %s = %s\n", name, p.Type(d.Schema, 0)))
+		output.WriteString(fmt.Sprintf("export const %s: z.ZodMiniType<%s> = %s", schemaName, name, zod))
+
+	default:
+		output.WriteString(fmt.Sprintf("export const %s = %s\n", schemaName, zod))
+		output.WriteString(jsdoc)
+		output.WriteString(fmt.Sprintf("export type %s = z.infer<typeof %s>", name, schemaName))
+	}
+
+	output.WriteString(enumObject(name, d.Schema))
+
+	return output.String()
+}
+
+// Schema prints a schema as a zod/mini expression. Objects are indented by the
+// given level, as they would be when written inside other objects at it.
+func (p ZodMiniPrinter) Schema(s Schema, indent int) string {
+	switch s := s.(type) {
+	case *Primitive:
+		return p.primitive(s)
+
+	case *Array:
+		return zodMiniCheck(fmt.Sprintf("z.array(%s)", p.Schema(s.Items, indent)), p.checks(s.Checks, "Length", "")...)
+
+	case *Object:
+		output := strings.Builder{}
+		output.WriteString("z.object({\n")
+		for _, f := range s.Fields {
+			output.WriteString(p.field(f, indent+1))
+		}
+		output.WriteString(indentation(indent))
+		output.WriteString("})")
+		return output.String()
+
+	case *Tuple:
+		items := make([]string, len(s.Items))
+		for i, item := range s.Items {
+			items[i] = p.Schema(item, indent)
+		}
+		return fmt.Sprintf("z.tuple([%s])", strings.Join(items, ", "))
+
+	case *Record:
+		return fmt.Sprintf("z.record(%s, %s)", p.Schema(s.Key, indent), p.Schema(s.Value, indent))
+
+	case *Union:
+		members := make([]string, len(s.Members))
+		for i, m := range s.Members {
+			members[i] = p.Schema(m, indent)
+		}
+		switch {
+		case s.Discriminator != "":
+			return fmt.Sprintf("z.discriminatedUnion(%s, [%s])", jsString(s.Discriminator), strings.Join(members, ", "))
+		case len(members) == 1:
+			return members[0]
+		}
+		return fmt.Sprintf("z.union([%s])", strings.Join(members, ", "))
+
+	case *OrEmpty:
+		return fmt.Sprintf("z.union([%s, %s])", p.Schema(s.Schema, indent), p.Schema(&Const{s.Value}, indent))
+
+	case *Reference:
+		name := schemaName(p.Prefix, s.Name)
+		if s.Lazy {
+			return fmt.Sprintf("z.lazy(() => %s)", name)
+		}
+		if len(s.Args) > 0 {
+			args := make([]string, len(s.Args))
+			for i, arg := range s.Args {
+				args[i] = p.Schema(arg, indent)
+			}
+			return fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+		}
+		return name
+
+	case *Custom:
+		if schema, ok := s.Libraries[p.Name()]; ok {
+			return schema
+		}
+		return s.Zod
+	}
+
+	// the rest are written the same as for Zod.
+	return ZodPrinter{Prefix: p.Prefix}.Schema(s, indent)
+}
+
+func (p ZodMiniPrinter) field(f *Field, indent int) string {
+	jsdoc, _ := formatDoc(f.Doc, indent)
+
+	schema := p.Schema(f.Schema, indent) + f.Methods
+	switch {
+	case f.Nullish:
+		schema = fmt.Sprintf("z.nullish(%s)", schema)
+	case f.Optional && f.Nullable:
+		schema = fmt.Sprintf("z.optional(z.nullable(%s))", schema)
+	case f.Optional:
+		schema = fmt.Sprintf("z.optional(%s)", schema)
+	case f.Nullable:
+		schema = fmt.Sprintf("z.nullable(%s)", schema)
+	}
+	if f.Default != "" {
+		schema = fmt.Sprintf("z._default(%s, %s)", schema, f.Default)
+	}
+	schema = zodMiniDescribe(schema, f.Description)
+
+	return fmt.Sprintf("%s%s%s: %s,\n", jsdoc, indentation(indent), f.Name, schema)
+}
+
+func (p ZodMiniPrinter) primitive(s *Primitive) string {
+	if s.Kind == PrimitiveBoolean && s.Coerce {
+		// z.coerce.boolean() would accept "false" as true, like Boolean().
+		return `z.pipe(z.enum(["true", "false"]), z.transform((v) => v === "true"))`
+	}
+
+	schema := ""
+	bound, suffix := "Value", ""
+	switch s.Kind {
+	case PrimitiveString:
+		schema = "z.string()"
+		bound = "Length"
+	case PrimitiveNumber:
+		schema = "z.number()"
+	case PrimitiveBoolean:
+		schema = "z.boolean()"
+	case PrimitiveBigInt:
+		schema = "z.bigint()"
+		suffix = "n"
+	case PrimitiveAny:
+		schema = "z.any()"
+	default:
+		schema = "z.unknown()"
+	}
+	if s.Coerce {
+		schema = strings.Replace(schema, "z.", "z.coerce.", 1)
+	}
+
+	// integers and string formats are schemas of their own in zod/mini rather
+	// than checks, so they replace the schema, or follow it when coerced.
+	formats, checks := []string{}, []Check{}
+	for _, check := range s.Checks {
+		switch check.Kind {
+		case CheckInt:
+			formats = append(formats, "z.int()")
+		case CheckEmail:
+			formats = append(formats, "z.email()")
+		case CheckURL:
+			formats = append(formats, "z.url()")
+		case CheckUUID:
+			formats = append(formats, "z.uuid()")
+		case CheckIP:
+			if check.Value == "" {
+				formats = append(formats, "z.union([z.ipv4(), z.ipv6()])")
+			} else {
+				formats = append(formats, fmt.Sprintf("z.ip%s()", check.Value))
+			}
+		default:
+			checks = append(checks, check)
+		}
+	}
+	for i, format := range formats {
+		if i == 0 && !s.Coerce {
+			schema = format
+		} else {
+			schema = fmt.Sprintf("z.pipe(%s, %s)", schema, format)
+		}
+	}
+
+	return zodMiniCheck(schema, p.checks(checks, bound, suffix)...)
+}
+
+// the zod/mini checks for each kind of check, with the value of the check.
+var zodMiniChecks = map[CheckKind]string{
+	CheckNonNegative:        "z.nonnegative()",
+	CheckGreaterThan:        "z.gt(%s)",
+	CheckGreaterThanOrEqual: "z.gte(%s)",
+	CheckLessThan:           "z.lt(%s)",
+	CheckLessThanOrEqual:    "z.lte(%s)",
+	CheckTrue:               "z.refine((v) => v)",
+	CheckLowercase:          "z.refine((v) => v === v.toLowerCase())",
+	CheckUppercase:          "z.refine((v) => v === v.toUpperCase())",
+}
+
+// prints the checks as zod/mini checks. bound is whether Min and Max are of the
+// Value or the Length, and suffix is added to the values of numbers, such as
+// `n` for bigints.
+func (p ZodMiniPrinter) checks(checks []Check, bound, suffix string) []string {
+	out := []string{}
+	for _, check := range checks {
+		switch check.Kind {
+		case CheckMin, CheckMax:
+			switch {
+			case bound == "Length" && check.Kind == CheckMin:
+				out = append(out, fmt.Sprintf("z.minLength(%s)", check.Value))
+			case bound == "Length":
+				out = append(out, fmt.Sprintf("z.maxLength(%s)", check.Value))
+			case check.Kind == CheckMin:
+				out = append(out, fmt.Sprintf("z.gte(%s%s)", check.Value, suffix))
+			default:
+				out = append(out, fmt.Sprintf("z.lte(%s%s)", check.Value, suffix))
+			}
+		case CheckLength:
+			out = append(out, fmt.Sprintf("z.length(%s)", check.Value))
+		case CheckNonZero:
+			out = append(out, fmt.Sprintf("z.refine((v) => v !== 0%s)", suffix))
+		case CheckRegex:
+			out = append(out, fmt.Sprintf("z.regex(/%s/)", check.Value))
+		case CheckStartsWith:
+			out = append(out, fmt.Sprintf("z.startsWith(%s)", jsString(check.Value)))
+		case CheckEndsWith:
+			out = append(out, fmt.Sprintf("z.endsWith(%s)", jsString(check.Value)))
+		case CheckIncludes:
+			out = append(out, fmt.Sprintf("z.includes(%s)", jsString(check.Value)))
+		case CheckGreaterThan, CheckGreaterThanOrEqual, CheckLessThan, CheckLessThanOrEqual:
+			out = append(out, fmt.Sprintf(zodMiniChecks[check.Kind], check.Value+suffix))
+		default:
+			out = append(out, zodMiniChecks[check.Kind])
+		}
+	}
+	return out
+}
+
+// Type prints the TypeScript type of the values a schema accepts once parsed,
+// which is written out by hand for declarations that refer to themselves.
+func (p ZodMiniPrinter) Type(s Schema, indent int) string {
+	return typePrinter{prefix: p.Prefix, parsed: true, argType: "z.ZodMiniType<%s>"}.typ(s, indent)
+}
+
+// runs the checks on the schema, if there are any.
+func zodMiniCheck(schema string, checks ...string) string {
+	if len(checks) == 0 {
+		return schema
+	}
+	return fmt.Sprintf("%s.check(%s)", schema, strings.Join(checks, ", "))
+}
+
+// registers the description of a schema, which zod/mini has no `.describe()`
+// for.
+func zodMiniDescribe(schema, text string) string {
+	if text == "" {
+		return schema
+	}
+	return fmt.Sprintf("%s.register(z.globalRegistry, { description: %s })", schema, jsString(text))
+}
+
+// a printer for Zod that can print schemas on their own, for ConvertType.
+type zodSchemaPrinter interface {
+	Library
+	Schema(s Schema, indent int) string
+}
+
+// gets the printer for Zod, which is ZodMiniPrinter when zod is imported from
+// zod/mini.
+func (c *Converter) zodPrinter() zodSchemaPrinter {
+	if c.preambleOrDefault().isZodMini() {
+		return ZodMiniPrinter{Prefix: c.prefix}
+	}
+	return ZodPrinter{Prefix: c.prefix}
+}
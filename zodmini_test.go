@@ -0,0 +1,103 @@
+package supervillain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZodMini(t *testing.T) {
+	type Signup struct {
+		Email    string            `json:"email" validate:"required,email"`
+		Handle   string            `json:"handle" validate:"lowercase,min=3,max=15"`
+		Age      uint8             `json:"age" validate:"gte=18"`
+		Count    int               `json:"count,string"`
+		Enabled  bool              `json:"enabled,string"`
+		Address  string            `json:"address" validate:"ip"`
+		Tags     []string          `json:"tags" validate:"max=5"`
+		Labels   map[string]string `json:"labels"`
+		Nickname *string           `json:"nickname,omitempty"`
+		Plan     string            `json:"plan" zod:"default=\"free\",describe=The plan signed up for"`
+		Note     string            `json:"note" zod:"nullish"`
+		Level    int               `json:"level" validate:"omitempty,lt=10"`
+	}
+
+	c := NewConverter(nil, WithValidationTags("validate"), WithQuotedMode(QuotedCoerce), WithPreamble(Preamble{Import: "zod/mini"}))
+	assert.Equal(t, `import { z } from "zod/mini"
+
+export const SignupSchema = z.object({
+  email: z.email().check(z.minLength(1)),
+  handle: z.string().check(z.refine((v) => v === v.toLowerCase()), z.minLength(3), z.maxLength(15)),
+  age: z.int().check(z.gte(0), z.lte(255), z.gte(18)),
+  count: z.pipe(z.coerce.number(), z.int()),
+  enabled: z.pipe(z.enum(["true", "false"]), z.transform((v) => v === "true")),
+  address: z.union([z.ipv4(), z.ipv6()]),
+  tags: z.nullable(z.array(z.string()).check(z.maxLength(5))),
+  labels: z.nullable(z.record(z.string(), z.string())),
+  nickname: z.optional(z.string()),
+  plan: z._default(z.string(), "free").register(z.globalRegistry, { description: "The plan signed up for" }),
+  note: z.nullish(z.string()),
+  level: z.union([z.int().check(z.lt(10)), z.literal(0)]),
+})
+export type Signup = z.infer<typeof SignupSchema>
+
+`, c.Convert(Signup{}))
+}
+
+func TestZodMiniBigInt(t *testing.T) {
+	type Ledger struct {
+		Total int64 `json:"total" validate:"required,lte=100"`
+	}
+
+	c := NewConverter(nil, WithValidationTags("validate"), WithInt64Mode(Int64BigInt))
+	assert.Equal(t, `export const LedgerSchema = z.object({
+  total: z.bigint().check(z.gte(-9223372036854775808n), z.lte(9223372036854775807n), z.refine((v) => v !== 0n), z.lte(100n)),
+})
+export type Ledger = z.infer<typeof LedgerSchema>
+
+`, c.ConvertLibrary(ZodMiniPrinter{}, []interface{}{Ledger{}}))
+}
+
+func TestZodMiniRecursive(t *testing.T) {
+	c := NewConverter(nil)
+	assert.Equal(t, `export type Category = {
+  name: string
+  parent: Category | null
+  children?: Category[]
+}
+export const CategorySchema: z.ZodMiniType<Category> = z.object({
+  name: z.string(),
+  parent: z.nullable(z.lazy(() => CategorySchema)),
+  children: z.optional(z.array(z.lazy(() => CategorySchema))),
+})
+
+`, c.ConvertLibrary(ZodMiniPrinter{}, []interface{}{Category{}}))
+}
+
+func TestZodMiniGeneric(t *testing.T) {
+	c := NewConverter(nil)
+	assert.Equal(t, `export const MemberSchema = z.object({
+  Name: z.string(),
+})
+export type Member = z.infer<typeof MemberSchema>
+
+export const PageSchema = <T extends z.ZodMiniType>(t: T) => z.object({
+  Items: z.nullable(z.array(t)),
+  Next: z.string(),
+})
+export type Page<T extends z.ZodMiniType> = z.infer<ReturnType<typeof PageSchema<T>>>
+
+export const PageOfStringSchema = z.object({
+  Items: z.nullable(z.array(z.string())),
+  Next: z.string(),
+})
+export type PageOfString = z.infer<typeof PageOfStringSchema>
+
+export const ListingSchema = z.object({
+  Names: PageOfStringSchema,
+  Members: PageSchema(MemberSchema),
+})
+export type Listing = z.infer<typeof ListingSchema>
+
+`, c.ConvertLibrary(ZodMiniPrinter{}, []interface{}{Listing{}}))
+}
@@ -0,0 +1,109 @@
+package supervillain
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidTag is reported for `zod` struct tags that cannot be understood.
+const ErrInvalidTag ErrorCode = "invalid_tag"
+
+// the overrides in a `zod` struct tag, such as
+// `zod:".email().max(255),nullable,describe=Where we send receipts"`.
+type zodTag struct {
+	// the field is left out of the schema, but is still in the JSON.
+	exclude bool
+	// replaces the schema of the field entirely.
+	schema string
+	// chained methods to append to the schema, such as `.email()`.
+	methods string
+	// forces the field to be optional, nullable or both.
+	optional bool
+	nullable bool
+	nullish  bool
+	// the JavaScript expression for `.default()`.
+	def string
+	// the text for `.describe()`, which may be quoted.
+	describe string
+}
+
+// parses the `zod` tag of a field. entries are separated by commas, except for
+// commas inside brackets or quotes so that schemas and methods can have more
+// than one argument.
+func (c *Converter) zodTag(f reflect.StructField) zodTag {
+	tag := zodTag{}
+
+	value, ok := f.Tag.Lookup("zod")
+	if !ok {
+		return tag
+	}
+	if value == "-" {
+		tag.exclude = true
+		return tag
+	}
+
+	for _, entry := range splitTopLevel(value) {
+		entry = strings.TrimSpace(entry)
+		key, arg, hasArg := strings.Cut(entry, "=")
+
+		switch {
+		case entry == "":
+		case strings.HasPrefix(entry, "."):
+			tag.methods += entry
+		case entry == "optional":
+			tag.optional = true
+		case entry == "nullable":
+			tag.nullable = true
+		case entry == "nullish":
+			tag.nullish = true
+		case hasArg && key == "schema":
+			tag.schema = arg
+		case hasArg && key == "default":
+			tag.def = arg
+		case hasArg && key == "describe":
+			// the text may be quoted so that it can contain commas.
+			if len(arg) >= 2 && (arg[0] == '\'' || arg[0] == '"') && arg[len(arg)-1] == arg[0] {
+				arg = arg[1 : len(arg)-1]
+			}
+			tag.describe = arg
+		default:
+			c.fail(f.Type, ErrInvalidTag, fmt.Sprintf("unknown entry in zod tag: %s", entry))
+		}
+	}
+
+	return tag
+}
+
+// splits s on the commas that are not inside brackets or quotes.
+func splitTopLevel(s string) []string {
+	parts := []string{}
+	depth := 0
+	quote := rune(0)
+	escaped := false
+	start := 0
+
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if r == '\\' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'' || r == '`':
+			quote = r
+		case r == '(' || r == '[' || r == '{':
+			depth++
+		case r == ')' || r == ']' || r == '}':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
@@ -0,0 +1,102 @@
+package supervillain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Invoice struct {
+	ID       string            `json:"id" zod:"schema=z.string().uuid()"`
+	Email    string            `json:"email" zod:".email().max(255)"`
+	Notes    string            `json:"notes" zod:"optional,describe='Shown to the customer, if set'"`
+	Total    float64           `json:"total" zod:"nullable,default=0"`
+	Currency string            `json:"currency" zod:"schema=z.enum([\"GBP\", \"EUR\"]),default=\"GBP\""`
+	Discount *float64          `json:"discount" zod:"nullish"`
+	Lines    []string          `json:"lines" zod:".max(10)"`
+	Meta     map[string]string `json:"meta" zod:"-"`
+	Secret   string            `json:"-" zod:"optional"`
+	Pattern  string            `json:"pattern" zod:".regex(/^[a-z]{1,3}$/),optional"`
+}
+
+func TestZodTag(t *testing.T) {
+	assert.Equal(t,
+		`export const InvoiceSchema = z.object({
+  id: z.string().uuid(),
+  email: z.string().email().max(255),
+  notes: z.string().optional().describe("Shown to the customer, if set"),
+  total: z.number().nullable().default(0),
+  currency: z.enum(["GBP", "EUR"]).default("GBP"),
+  discount: z.number().nullish(),
+  lines: z.string().array().max(10).nullable(),
+  pattern: z.string().regex(/^[a-z]{1,3}$/).optional(),
+})
+export type Invoice = z.infer<typeof InvoiceSchema>
+
+`,
+		StructToZodSchema(Invoice{}))
+}
+
+func TestZodTagCustomNullable(t *testing.T) {
+	type Order struct {
+		Plain  *Visibility
+		Forced *Visibility `zod:"nullable"`
+	}
+	assert.Equal(t,
+		`export const OrderSchema = z.object({
+  Plain: z.string(),
+  Forced: z.string().nullable(),
+})
+export type Order = z.infer<typeof OrderSchema>
+
+`,
+		StructToZodSchema(Order{}))
+}
+
+type Topic struct {
+	Title   string   `zod:".min(1)"`
+	Parent  *Topic   `zod:"optional"`
+	Replies []*Topic `zod:"schema=z.array(z.lazy(() => TopicSchema))"`
+	Hidden  bool     `zod:"-"`
+}
+
+func TestZodTagRecursive(t *testing.T) {
+	assert.Equal(t,
+		`export type Topic = {
+  Title: string
+  Parent?: Topic | null
+  Replies: unknown | null
+}
+export const TopicSchema: z.ZodType<Topic> = z.object({
+  Title: z.string().min(1),
+  Parent: z.lazy(() => TopicSchema).optional().nullable(),
+  Replies: z.array(z.lazy(() => TopicSchema)).nullable(),
+})
+
+`,
+		StructToZodSchema(Topic{}))
+}
+
+func TestZodTagInvalid(t *testing.T) {
+	type Account struct {
+		Name string `zod:"optinal"`
+	}
+
+	c := NewConverter(nil)
+	_, err := c.ConvertE(Account{})
+
+	var errs ConversionErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "Account.Name: unknown entry in zod tag: optinal (invalid_tag)", errs[0].Error())
+}
+
+func TestSplitTopLevel(t *testing.T) {
+	assert.Equal(t, []string{"a", "b"}, splitTopLevel("a,b"))
+	assert.Equal(t, []string{`schema=z.enum(["a", "b"])`, "optional"}, splitTopLevel(`schema=z.enum(["a", "b"]),optional`))
+	assert.Equal(t, []string{`default="a,b"`, "x"}, splitTopLevel(`default="a,b",x`))
+	assert.Equal(t, []string{`default="a\",b"`}, splitTopLevel(`default="a\",b"`))
+	assert.Equal(t, []string{".refine((v) => v.x, { message: 'a, b' })"}, splitTopLevel(".refine((v) => v.x, { message: 'a, b' })"))
+}